@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// archiveOption defines a command branch that contains only the archive command.
+type archiveOption struct {
+	// Archive to read the tag vectors from an image archive.
+	Archive archiveCmd `cmd:"" help:"read the distribution of an image from a docker save tarball or an OCI image layout"`
+}
+
+// archiveCmd defines a command to read tag vectors from an image archive.
+type archiveCmd struct {
+	Context tuplipContext `embed:""`
+	// Archive is the image archive tarball or the OCI image layout directory.
+	Archive string `arg:"" type:"path" help:"the docker save tarball, or the OCI image layout directory or tarball"`
+	// Options contain the parameters for reading the image archive.
+	Options tupliplib.ArchiveOptions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the given image archive as input.
+func (c archiveCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromArchive(c.Archive, c.Options); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// bakeOption defines a command branch that contains only the bake command.
+type bakeOption struct {
+	// Bake to read the tag vectors from the targets of a buildx bake file.
+	Bake bakeCmd `cmd:"" help:"read the tag vectors from the Dockerfiles of the targets in a buildx bake file"`
+}
+
+// bakeCmd defines a command to read tag vectors from the targets of a buildx bake file.
+type bakeCmd struct {
+	Context tuplipContext `embed:""`
+	// File is the buildx bake file.
+	File string `arg:"" optional:"" type:"existingfile" help:"the buildx bake file in HCL or JSON format (default: docker-bake.hcl or docker-bake.json)"`
+	// Options contain the parameters for reading the bake file.
+	Options tupliplib.BakeOptions `embed:""`
+	// FileOptions contain the parameters for reading the Dockerfiles.
+	FileOptions tupliplib.FileOptions `embed:""`
+	// Conventions contain the names that are used to interpret the Dockerfiles.
+	Conventions tupliplib.Conventions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command for each target in the given bake file.
+func (c bakeCmd) Run(ctx *kong.Context) error {
+	c.Context = c.Context.withDockerfiles(c.Conventions, c.FileOptions)
+	tuplip := c.Context.tuplip()
+	sources, err := (&tuplip).FromBake(c.File, c.Options, c.FileOptions)
+	if err != nil {
+		return err
+	}
+	if err = c.Context.requireSingleSource(ctx, sources, "--bake-target"); err != nil {
+		return err
+	}
+	return c.Context.toRoots(ctx, sources)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// ciOption defines a command branch that contains only the ci command.
+type ciOption struct {
+	// CI to read the tag vectors from the environment of a CI system.
+	CI ciCmd `cmd:"" name:"ci" help:"read the root version from the release tag, or the channel vector from the branch or pull request that GitHub Actions, GitLab CI, or Docker Hub builds"`
+}
+
+// ciCmd defines a command to read tag vectors from the environment of a CI system.
+type ciCmd struct {
+	Context tuplipContext `embed:""`
+	// Options contain the parameters for reading the CI environment.
+	Options tupliplib.CIOptions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the environment of the CI system as input.
+func (c ciCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromCI(os.Environ(), c.Options); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// composeOption defines a command branch that contains only the compose command.
+type composeOption struct {
+	// Compose to read the tag vectors from the build definitions of a docker-compose file.
+	Compose composeCmd `cmd:"" help:"read the tag vectors from the Dockerfiles of the services in a docker-compose file"`
+}
+
+// composeCmd defines a command to read tag vectors from the build definitions of a docker-compose file.
+type composeCmd struct {
+	Context tuplipContext `embed:""`
+	// File is the docker-compose file.
+	File string `arg:"" optional:"" type:"existingfile" help:"the docker-compose file (default: compose.yaml, compose.yml, docker-compose.yaml, or docker-compose.yml)"`
+	// Options contain the parameters for reading the docker-compose file.
+	Options tupliplib.ComposeOptions `embed:""`
+	// FileOptions contain the parameters for reading the Dockerfiles.
+	FileOptions tupliplib.FileOptions `embed:""`
+	// Conventions contain the names that are used to interpret the Dockerfiles.
+	Conventions tupliplib.Conventions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command for each service in the given compose file.
+func (c composeCmd) Run(ctx *kong.Context) error {
+	c.Context = c.Context.withDockerfiles(c.Conventions, c.FileOptions)
+	tuplip := c.Context.tuplip()
+	sources, err := (&tuplip).FromCompose(c.File, c.Options, c.FileOptions)
+	if err != nil {
+		return err
+	}
+	if err = c.Context.requireSingleSource(ctx, sources, "--service"); err != nil {
+		return err
+	}
+	return c.Context.toRoots(ctx, sources)
+}
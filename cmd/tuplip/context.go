@@ -9,6 +9,7 @@ import (
 	"github.com/gofunky/tuplip/pkg/tupliplib"
 	"github.com/oleiade/reflections"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -18,16 +19,89 @@ type rootCmd interface {
 	run(src *tupliplib.TuplipSource) (*stream.Stream, error)
 }
 
+// batchCmd wraps the root commands that process all sources of a command at once.
+type batchCmd interface {
+	// runAll executes the command given the options of the tuplip context and all tuplip sources.
+	runAll(tuplip *tupliplib.Tuplip, sources []*tupliplib.TuplipSource) (*stream.Stream, error)
+}
+
+// silentCmd wraps the root commands that may omit printing their Docker tags.
+type silentCmd interface {
+	// silent determines if the Docker tags are omitted from the stdout.
+	silent() bool
+}
+
+// sourceImageCmd wraps the root commands that tag a source image.
+type sourceImageCmd interface {
+	// sourceImage returns the tag of the source image, or an empty string if no image is tagged.
+	sourceImage() string
+}
+
 // tuplipContext provides the options and the interface to the tupliplib.
 type tuplipContext struct {
 	tupliplib.Tuplip `embed:""`
+	// Merge defines the additional sources that are merged with the source of the command.
+	Merge mergeFlags `embed:""`
+	// Output is the format of the written Docker tags.
+	Output tupliplib.OutputFormat `enum:"text,json,yaml,csv,nul" default:"text" help:"the format of the written Docker tags (text, json, yaml, csv, or nul)"`
+	// CI contains the parameters for writing the Docker tags to the outputs of a CI system.
+	CI tupliplib.CIOutputOptions `embed:""`
+	// Extraction contains the rules that extract the tag vectors from the tags of base images and remote tags.
+	Extraction extractionFlags `embed:""`
+}
+
+// extractionFlags define the extraction rules of the tuplip conventions. They are part of the tuplip context instead
+// of the conventions of the Dockerfile commands, so that they also apply to the remote tags of the find command for
+// all sources.
+type extractionFlags struct {
+	// Rules extract the tag vectors from the tags of matching images before the default heuristic is used.
+	Rules []tupliplib.ExtractionRule `name:"extract-rule" sep:"none" placeholder:"IMAGE=REGEX" help:"extract the tag vectors from the tags of the matching images using a regular expression with named groups"`
+	// Presets enables the built-in extraction rules for popular official images.
+	Presets bool `name:"extract-presets" help:"enable the built-in extraction rules for popular official images"`
+}
+
+// mergeFlags define the additional sources that are merged with the source of a command.
+type mergeFlags struct {
+	// Vectors are additional tag vectors that take precedence over the source of the command.
+	Vectors []string `name:"vector" placeholder:"VECTOR" help:"merge the given tag vector with precedence over the source"`
+	// Env merges the tag vectors of the environment variables with precedence over the source of the command.
+	Env bool `help:"merge the tag vectors of the environment variables with precedence over the source"`
+	// EnvOptions contain the parameters for reading the environment variables.
+	EnvOptions tupliplib.EnvOptions `embed:""`
+	// GoMod is an additional go.mod file that has a lower precedence than the source of the command.
+	GoMod string `name:"merge-go-mod" type:"existingfile" placeholder:"FILE" help:"merge the Go version and the module dependency vectors of the given go.mod file with a lower precedence than the source"`
+	// GoModOptions contain the parameters for reading the go.mod files.
+	GoModOptions tupliplib.GoModOptions `embed:""`
+	// Pins are additional runtime pin files that have a lower precedence than the source of the command.
+	Pins []string `name:"merge-pin" type:"existingfile" placeholder:"FILE" help:"merge the tool versions of the given runtime pin file with a lower precedence than the source"`
+	// PinOptions contain the parameters for reading the runtime pin files.
+	PinOptions tupliplib.PinOptions `embed:""`
+	// Files are additional Dockerfiles that have a lower precedence than the source of the command.
+	Files []string `name:"merge-file" type:"existingfile" placeholder:"FILE" help:"merge the tag vectors of the given Dockerfile with a lower precedence than the source"`
+	// Options contain the parameters for merging the sources.
+	Options tupliplib.MergeOptions `embed:""`
+	// FileOptions contain the parameters of the command for reading the Dockerfiles of the merge flags.
+	FileOptions tupliplib.FileOptions `kong:"-"`
 }
 
 // sourceOption defines a command branch to determine the source of the tag vectors.
 type sourceOption struct {
-	stdinOption `embed:""`
-	fileOption  `embed:""`
-	paramOption `embed:""`
+	stdinOption    `embed:""`
+	fileOption     `embed:""`
+	dirOption      `embed:""`
+	gitOption      `embed:""`
+	gomodOption    `embed:""`
+	pinsOption     `embed:""`
+	envOption      `embed:""`
+	ciOption       `embed:""`
+	sbomOption     `embed:""`
+	archiveOption  `embed:""`
+	imageOption    `embed:""`
+	libraryOption  `embed:""`
+	composeOption  `embed:""`
+	bakeOption     `embed:""`
+	manifestOption `embed:""`
+	paramOption    `embed:""`
 }
 
 // fromRepositoryOption defines a command branch to determine the source of the tag vectors and a repository name.
@@ -66,23 +140,196 @@ type processingFlags struct {
 	Straight bool `short:"s" help:"use the input tags directly without any mixing"`
 }
 
+// tuplip returns the options of the tuplip context with the extraction rules of the extraction flags.
+func (t tuplipContext) tuplip() tupliplib.Tuplip {
+	tuplip := t.Tuplip
+	tuplip.Conventions.ExtractionRules = t.Extraction.Rules
+	tuplip.Conventions.ExtractionPresets = t.Extraction.Presets
+	return tuplip
+}
+
+// withDockerfiles returns the context with the given conventions and parameters of a Dockerfile command, so that the
+// Dockerfiles of the merge flags are read like the ones of the command.
+func (t tuplipContext) withDockerfiles(conventions tupliplib.Conventions,
+	options tupliplib.FileOptions) tuplipContext {
+
+	t.Tuplip.Conventions = conventions
+	t.Merge.FileOptions = options
+	return t
+}
+
 // toRoot determines the root command and passes the given tuplip source to it.
-func (t tuplipContext) toRoot(ctx *kong.Context, src *tupliplib.TuplipSource) error {
-	command := strings.SplitN(ctx.Command(), " ", 2)[0]
-	capCommand := strings.Title(command)
-	cmd, err := reflections.GetField(cli, capCommand)
+// The given source is merged with the sources of the merge flags first.
+func (t tuplipContext) toRoot(ctx *kong.Context, src *tupliplib.TuplipSource) (err error) {
+	if src, err = t.merge(src); err != nil {
+		return err
+	}
+	return t.process(ctx, []*tupliplib.TuplipSource{src})
+}
+
+// toRoots determines the root command and passes the given tuplip sources to it.
+// Each source is merged with the sources of the merge flags first.
+func (t tuplipContext) toRoots(ctx *kong.Context, sources []*tupliplib.TuplipSource) (err error) {
+	for i, src := range sources {
+		if sources[i], err = t.merge(src); err != nil {
+			return err
+		}
+	}
+	return t.process(ctx, sources)
+}
+
+// rootCommand returns the name and the options of the root command of the given context.
+func rootCommand(ctx *kong.Context) (command string, cmd interface{}, err error) {
+	command = strings.SplitN(ctx.Command(), " ", 2)[0]
+	cmd, err = reflections.GetField(cli, strings.Title(command))
+	return command, cmd, err
+}
+
+// requireSingleSource fails if the root command tags a source image and multiple sources are given, since the image
+// would receive the Docker tags of all of them. The sources of the same manifest image count as one source, since
+// they only differ in their target repository. The given flag is suggested to select a single source.
+func (t tuplipContext) requireSingleSource(ctx *kong.Context, sources []*tupliplib.TuplipSource, flag string) error {
+	count := len(sources)
+	images := make(map[string]bool)
+	for _, src := range sources {
+		if image := src.Image(); image != "" {
+			if images[image] {
+				count--
+			}
+			images[image] = true
+		}
+	}
+	if count <= 1 {
+		return nil
+	}
+	command, cmd, err := rootCommand(ctx)
 	if err != nil {
 		return err
 	}
-	rootCmd := cmd.(rootCmd)
-	stm, err := rootCmd.run(src)
+	if image, ok := cmd.(sourceImageCmd); ok && image.sourceImage() != "" {
+		return fmt.Errorf("the %s command tags the single image '%s', but %d sources were found; select one with %s",
+			command, image.sourceImage(), count, flag)
+	}
+	return nil
+}
+
+// process executes the root command for the given sources and writes the results.
+// Batch commands process all sources at once and write their documents line by line. Other root commands process each
+// source separately. The Docker tags of all sources are written to the outputs of the CI system, and they are written
+// grouped by their source in the output format.
+func (t tuplipContext) process(ctx *kong.Context, sources []*tupliplib.TuplipSource) error {
+	command, cmd, err := rootCommand(ctx)
 	if err != nil {
 		return err
 	}
-	return t.write(stm)
+	if batch, ok := cmd.(batchCmd); ok {
+		if t.Output != tupliplib.TextOutput {
+			return fmt.Errorf("the %s command does not support the output format '%s'", command, t.Output)
+		}
+		tuplip := t.tuplip()
+		stm, err := batch.runAll(&tuplip, sources)
+		if err != nil {
+			return err
+		}
+		return t.write(stm)
+	}
+	rootCmd := cmd.(rootCmd)
+	var tags []string
+	var groups []tupliplib.TagGroup
+	for _, src := range sources {
+		stm, err := rootCmd.run(src)
+		if err != nil {
+			return err
+		}
+		collector := collectors.Slice()
+		stm.Into(collector)
+		if err = <-stm.Open(); err != nil {
+			return err
+		}
+		group := tupliplib.TagGroup{Source: sourceName(src)}
+		for _, item := range collector.Get() {
+			group.Tags = append(group.Tags, fmt.Sprint(item))
+		}
+		tags = append(tags, group.Tags...)
+		groups = append(groups, group)
+	}
+	if err = tupliplib.WriteCIOutputs(os.Environ(), tags, t.CI); err != nil {
+		return err
+	}
+	if silent, ok := cmd.(silentCmd); ok && silent.silent() {
+		return nil
+	}
+	writer := bufio.NewWriter(os.Stdout)
+	if err = tupliplib.WriteTagGroups(writer, groups, t.Output); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// sourceName names the given source by its Dockerfile relative to the working directory if possible, or by its
+// repository otherwise.
+func sourceName(src *tupliplib.TuplipSource) string {
+	if src.File() == "" {
+		return src.Repository
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, src.File()); err == nil {
+			return rel
+		}
+	}
+	return src.File()
+}
+
+// merge combines the given source with the sources of the merge flags.
+// The vectors of the merge flags take precedence over the ones of the environment variables, the given source, the
+// go.mod file, and the pin files of the merge flags in this order. The Dockerfiles of the merge flags have the
+// lowest precedence. They are read with the conventions and the Dockerfile parameters of the command except for the
+// root version and the stage selection, which are specific to the Dockerfiles of the command.
+func (t tuplipContext) merge(src *tupliplib.TuplipSource) (*tupliplib.TuplipSource, error) {
+	tuplip := t.tuplip()
+	var sources []*tupliplib.TuplipSource
+	if len(t.Merge.Vectors) > 0 {
+		sources = append(sources, (&tuplip).FromSlice(t.Merge.Vectors))
+	}
+	if t.Merge.Env {
+		envSrc, err := (&tuplip).FromEnv(os.Environ(), t.Merge.EnvOptions)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, envSrc)
+	}
+	sources = append(sources, src)
+	if t.Merge.GoMod != "" {
+		goModSrc, err := (&tuplip).FromGoMod(t.Merge.GoMod, t.Merge.GoModOptions)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, goModSrc)
+	}
+	if len(t.Merge.Pins) > 0 {
+		pinSrc, err := (&tuplip).FromPins(t.Merge.Pins, t.Merge.PinOptions)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, pinSrc)
+	}
+	fileOptions := t.Merge.FileOptions
+	fileOptions.RootVersion = ""
+	fileOptions.Target, fileOptions.IncludeStages, fileOptions.ExcludeStages = "", nil, nil
+	for _, file := range t.Merge.Files {
+		fileSrc, err := (&tuplip).FromFile(file, fileOptions)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, fileSrc)
+	}
+	if len(sources) == 1 {
+		return src, nil
+	}
+	return tupliplib.Merge(t.Merge.Options, sources...)
 }
 
-// write the results from the given tuplip stream to the stdout.
+// write the lines from the given tuplip stream to the stdout.
 func (t tuplipContext) write(stream *stream.Stream) error {
 	lineSplit := func(input string) string {
 		return fmt.Sprintln(input)
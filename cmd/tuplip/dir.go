@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// dirOption defines a command branch that contains only the dir command.
+type dirOption struct {
+	// Dir to read the tag vectors from all Dockerfiles in a directory.
+	Dir dirCmd `cmd:"" help:"read the tag vectors from all Dockerfiles in a directory"`
+}
+
+// dirCmd defines a command to read tag vectors from all Dockerfiles in a directory.
+type dirCmd struct {
+	Context tuplipContext `embed:""`
+	// Directory is the directory that is scanned for Dockerfiles.
+	Directory string `arg:"" optional:"" type:"existingdir" help:"the directory that is scanned for Dockerfiles (default: the working directory)"`
+	// Options contain the parameters for discovering the Dockerfiles.
+	Options tupliplib.DirectoryOptions `embed:""`
+	// FileOptions contain the parameters for reading the Dockerfiles.
+	FileOptions tupliplib.FileOptions `embed:""`
+	// Conventions contain the names that are used to interpret the Dockerfiles.
+	Conventions tupliplib.Conventions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command for each Dockerfile in the given directory.
+func (c dirCmd) Run(ctx *kong.Context) error {
+	c.Context = c.Context.withDockerfiles(c.Conventions, c.FileOptions)
+	tuplip := c.Context.tuplip()
+	sources, err := (&tuplip).FromDirectory(c.Directory, c.Options, c.FileOptions)
+	if err != nil {
+		return err
+	}
+	if err = c.Context.requireSingleSource(ctx, sources, "--pattern"); err != nil {
+		return err
+	}
+	return c.Context.toRoots(ctx, sources)
+}
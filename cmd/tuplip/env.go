@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/alecthomas/kong"
+)
+
+// envOption defines a command branch that contains only the env command.
+type envOption struct {
+	// Env to read the tag vectors from environment variables.
+	Env envCmd `cmd:"" help:"read the tag vectors from environment variables such as TUPLIP_ROOT, TUPLIP_ALIAS, and TUPLIP_VECTOR_<NAME>"`
+}
+
+// envCmd defines a command to read tag vectors from environment variables.
+// The prefix and the vector mapping are taken from the merge flags of the context.
+type envCmd struct {
+	Context tuplipContext `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the environment variables as input.
+func (c envCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	c.Context.Merge.Env = false
+	if src, err := (&tuplip).FromEnv(os.Environ(), c.Context.Merge.EnvOptions); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
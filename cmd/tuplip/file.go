@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
 )
 
 // fileOption defines a command branch that contains only the file command.
@@ -15,14 +16,17 @@ type fileCmd struct {
 	Context tuplipContext `embed:""`
 	// File is the Dockerfile that contains the vectors as FROM instructions.
 	File string `arg:"" type:"existingfile" help:"the Dockerfile containing the vectors as FROM instructions"`
-	// RootVersion overrides the version of the root tag vector from the Dockerfile.
-	RootVersion string `short:"r" help:"override the version of the root tag vector from the Dockerfile"`
+	// Options contain the parameters for reading the Dockerfile.
+	Options tupliplib.FileOptions `embed:""`
+	// Conventions contain the names that are used to interpret the Dockerfile.
+	Conventions tupliplib.Conventions `embed:""`
 }
 
 // Run implements a dynamic interface from kong by executing a command using given file argument as input.
 func (c fileCmd) Run(ctx *kong.Context) error {
-	tuplip := c.Context.Tuplip
-	if src, err := (&tuplip).FromFile(c.File, c.RootVersion); err != nil {
+	c.Context = c.Context.withDockerfiles(c.Conventions, c.Options)
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromFile(c.File, c.Options); err != nil {
 		return err
 	} else {
 		return c.Context.toRoot(ctx, src)
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// gitOption defines a command branch that contains only the git command.
+type gitOption struct {
+	// Git to read the tag vectors from a local git repository.
+	Git gitCmd `cmd:"" help:"read the root version and the branch vector from a local git repository"`
+}
+
+// gitCmd defines a command to read tag vectors from a local git repository.
+type gitCmd struct {
+	Context tuplipContext `embed:""`
+	// Directory is the working tree of the git repository.
+	Directory string `arg:"" optional:"" type:"existingdir" help:"the working tree of the git repository (default: the working directory)"`
+	// Options contain the parameters for reading the git repository.
+	Options tupliplib.GitOptions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the given git repository as input.
+func (c gitCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromGit(c.Directory, c.Options); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
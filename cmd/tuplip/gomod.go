@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+)
+
+// gomodOption defines a command branch that contains only the gomod command.
+type gomodOption struct {
+	// Gomod to read the tag vectors from a go.mod file.
+	Gomod gomodCmd `cmd:"" name:"gomod" help:"read the Go version and the module dependency vectors from a go.mod file"`
+}
+
+// gomodCmd defines a command to read tag vectors from a go.mod file.
+// The Go alias and the module patterns are taken from the merge flags of the context.
+type gomodCmd struct {
+	Context tuplipContext `embed:""`
+	// File is the go.mod file.
+	File string `arg:"" optional:"" type:"existingfile" help:"the go.mod file (default: go.mod in the working directory)"`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the given go.mod file as input.
+func (c gomodCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromGoMod(c.File, c.Context.Merge.GoModOptions); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
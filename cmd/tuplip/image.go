@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// imageOption defines a command branch that contains only the image command.
+type imageOption struct {
+	// Image to read the tag vectors from a local image.
+	Image imageCmd `cmd:"" help:"read the version label and the version ENV keys of a local image by inspecting it with the Docker engine"`
+}
+
+// imageCmd defines a command to read tag vectors from a local image.
+type imageCmd struct {
+	Context tuplipContext `embed:""`
+	// Image is the reference of the local image.
+	Image string `arg:"" optional:"" help:"the reference of the local image (default: the source tag of the tag or push command)"`
+	// Options contain the parameters for reading the image.
+	Options tupliplib.ImageOptions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the given local image as input.
+// The image defaults to the source tag of the tag and push commands.
+func (c imageCmd) Run(ctx *kong.Context) error {
+	image := c.Image
+	if image == "" {
+		switch strings.SplitN(ctx.Command(), " ", 2)[0] {
+		case "tag":
+			image = cli.Tag.SourceTag.SourceTag
+		case "push":
+			image = cli.Push.SourceTag.SourceTag
+		}
+	}
+	if image == "" {
+		return errors.New("the image command requires an image reference if no source tag is given")
+	}
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromImage(image, c.Options); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// libraryCmd contains the options for the library command.
+type libraryCmd struct {
+	// Maintainers are the maintainers of the image in the library header.
+	Maintainers []string `name:"maintainer" placeholder:"MAINTAINER" help:"add a maintainer of the image to the library header"`
+	// GitRepo is the git repository of the library entries.
+	GitRepo string `placeholder:"URL" help:"the git repository of the library entries"`
+	// GitCommit is the git commit of the library entries.
+	GitCommit string `placeholder:"COMMIT" help:"the git commit of the library entries"`
+	// Architectures are the architectures of the library entries.
+	Architectures []string `name:"architecture" placeholder:"ARCH" help:"add an architecture of the library entries (e.g., amd64 or arm64v8)"`
+	// BaseDir is the directory that the library directories are relative to.
+	BaseDir string `type:"existingdir" placeholder:"DIR" help:"the directory that the directories of the Dockerfiles are relative to (default: the top level of their git repository)"`
+	// From command determines the source of the tag vectors.
+	From sourceOption `cmd:"" help:"determine the source of the tag vectors"`
+}
+
+// runAll implements main.batchCmd.runAll by exporting the tags of the given sources as library with one entry per
+// source.
+func (s libraryCmd) runAll(_ *tupliplib.Tuplip, sources []*tupliplib.TuplipSource) (*stream.Stream, error) {
+	header := tupliplib.LibraryEntry{
+		Architectures: s.Architectures,
+		GitCommit:     s.GitCommit,
+	}
+	if len(s.Maintainers) > 0 {
+		header.Fields = append(header.Fields, tupliplib.LibraryField{
+			Name:  "Maintainers",
+			Value: strings.Join(s.Maintainers, ", "),
+		})
+	}
+	if s.GitRepo != "" {
+		header.Fields = append(header.Fields, tupliplib.LibraryField{Name: "GitRepo", Value: s.GitRepo})
+	}
+	library, err := tupliplib.BuildLibrary(header, s.BaseDir, sources...)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err = library.Write(&buffer); err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	return stream.New(emitters.Slice(lines)), nil
+}
+
+// libraryOption defines a command branch that contains only the library source command.
+type libraryOption struct {
+	// Library to read the tags from an official-images library file.
+	Library librarySourceCmd `cmd:"" help:"read the tags of the entries of an official-images library file"`
+}
+
+// librarySourceCmd defines a command to read the tags from an official-images library file.
+type librarySourceCmd struct {
+	Context tuplipContext `embed:""`
+	// File is the library file.
+	File string `arg:"" type:"existingfile" help:"the official-images library file (e.g., library/golang)"`
+	// Options contain the parameters for reading the library file.
+	Options tupliplib.LibraryOptions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command for each entry in the given library file.
+func (c librarySourceCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	sources, err := (&tuplip).FromLibrary(c.File, c.Options)
+	if err != nil {
+		return err
+	}
+	if err = c.Context.requireSingleSource(ctx, sources, "--directory"); err != nil {
+		return err
+	}
+	return c.Context.toRoots(ctx, sources)
+}
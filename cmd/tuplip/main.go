@@ -15,6 +15,8 @@ var cli struct {
 	Help helpCmd `cmd:"" help:"show help for a command"`
 	// Graph prints the command graph.
 	Graph graphCmd `cmd:"" help:"print the command graph"`
+	// Schema prints the manifest schema.
+	Schema schemaCmd `cmd:"" help:"print the JSON schema of the tuplip manifest"`
 	// Build describes the build command.
 	Build buildCmd `cmd:"" help:"build Docker tags from the given tag vectors"`
 	// Tag describes the tag command.
@@ -23,6 +25,10 @@ var cli struct {
 	Push pushCmd `cmd:"" help:"tag and push the given source image with the Docker tags from the given tag vectors"`
 	// Find describes the find command.
 	Find findCmd `cmd:"" help:"find the most appropriate Docker tag in the given repository"`
+	// Library describes the library command.
+	Library libraryCmd `cmd:"" help:"export the Docker tags of the given sources in the official-images library format"`
+	// Supported describes the supported command.
+	Supported supportedCmd `cmd:"" help:"render a supported tags section with the Docker tags of the given sources"`
 	// Verbose mode enables detailed logging messages.
 	Verbose bool `short:"v" help:"print detailed logging messages"`
 }
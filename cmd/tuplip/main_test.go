@@ -2,12 +2,31 @@ package main
 
 import (
 	"github.com/rendon/testcli"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
 
 const WithoutRepository = "../../test/WithoutRepository.Dockerfile"
 const WithRepository = "../../test/WithRepository.Dockerfile"
+const Manifest = "../../test/tuplip.yaml"
+const Args = "../../test/Args.Dockerfile"
+const Targets = "../../test/Targets.Dockerfile"
+const Env = "../../test/Env.Dockerfile"
+const Conventions = "../../test/Conventions.Dockerfile"
+const Directives = "../../test/Directives.Dockerfile"
+const Extraction = "../../test/Extraction.Dockerfile"
+const Images = "../../test/images"
+const GoMod = "../../test/service.go.mod"
+const MinimalGoMod = "../../test/minimal.go.mod"
+const ToolVersions = "../../test/pins/.tool-versions"
+const JavaVersion = "../../test/pins/.java-version"
+const Compose = "../../test/builds/compose.yaml"
+const Bake = "../../test/builds/docker-bake.hcl"
+const SPDX = "../../test/sbom/image.spdx.json"
+const OCILayout = "../../test/archive"
+const Library = "../../test/library/app"
 
 func TestBuild(t *testing.T) {
 	type testBuild struct {
@@ -105,6 +124,7 @@ func TestBuild(t *testing.T) {
 			replace: true,
 		},
 	}
+	dotenv := filepath.Join(t.TempDir(), "tuplip.env")
 	tests := []testBuild{
 		{
 			args: []string{"build", "from", "file", WithoutRepository},
@@ -160,6 +180,30 @@ func TestBuild(t *testing.T) {
 				"gofunky/ignore:foo": false,
 			},
 		},
+		{
+			args: []string{"build", "from", "manifest", Manifest, "--image=docker"},
+			stdErr: map[string]bool{
+				"queueing read from manifest": true,
+				"queueing build":              true,
+			},
+			stdOut: map[string]bool{
+				"goo":             true,
+				"gofunky/git:goo": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "manifest", Manifest},
+			stdErr: map[string]bool{
+				"the tag command tags the single image 'source', but 2 sources were found; select one with --image": true,
+				"docker tag": false,
+			},
+			wantErr: true,
+		},
+		{
+			args:    []string{"build", "from", "manifest", Manifest, "--image=unknown"},
+			stdErr:  map[string]bool{"could not be found": true},
+			wantErr: true,
+		},
 		{
 			args: []string{"find", "from", "file", WithRepository},
 			stdErr: map[string]bool{
@@ -180,6 +224,24 @@ func TestBuild(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			args: []string{"find", "in", "gofunky/git", "from", "foo", "goo", "--extract-presets",
+				"--extract-rule=git=(?P<version>[0-9.]+)-(?P<alias_base>[a-z]+)"},
+			stdErr: map[string]bool{
+				"queueing find": true,
+				"fetching tags": true,
+				"unknown flag":  false,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"find", "in", "gofunky/git", "from", "foo", "goo", "--extract-rule=git=("},
+			stdErr: map[string]bool{
+				"invalid regular expression in extraction rule for 'git'": true,
+				"queueing find": false,
+			},
+			wantErr: true,
+		},
 		{
 			args:    []string{"version"},
 			stdErr:  map[string]bool{"version": true},
@@ -208,6 +270,428 @@ func TestBuild(t *testing.T) {
 				"6.3.8":                    false,
 			},
 		},
+		{
+			args: []string{"tag", "source", "from", "file", Args, "--build-arg=GO_VERSION=1.23.0"},
+			stdErr: map[string]bool{
+				"queueing read from Dockerfile":               true,
+				"docker tag source gofunky/args:golang1.23.0": true,
+				"docker tag source gofunky/args:alpine3.19":   true,
+				"docker tag source gofunky/args:golang1.22.3": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Targets, "--target=test"},
+			stdErr: map[string]bool{
+				"ignoring build stage":                  true,
+				"docker tag source golang1.22.3":        true,
+				"docker tag source alpine":              false,
+				"docker tag source golang1.22.3-node20": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Env, "--vector-key=*_VERSION"},
+			stdErr: map[string]bool{
+				"docker tag source gofunky/env:golang1.22.3\"":                      true,
+				"docker tag source gofunky/env:node20.11.0-npm10.2.4-yarn1.22.19\"": true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Conventions, "--version-arg=RELEASE",
+				"--repository-arg=TARGET_REPOSITORY", "--ignore-prefix=skip_", "--vector-arg-prefix=TUPLIP_"},
+			stdErr: map[string]bool{
+				"docker tag source gofunky/conventions:alpine3.19-golang1.22.3-slim\"": true,
+				"node": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Directives},
+			stdErr: map[string]bool{
+				"docker tag source alpine3-go1.22.3\"": true,
+				"docker tag source alpine3\"":          false,
+				"go1\"":                                false,
+				"node":                                 false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Extraction, "--extract-presets",
+				"--extract-rule=gradle=jdk(?P<java>[0-9]{1,3})"},
+			stdErr: map[string]bool{
+				"docker tag source gradle-jammy-java17-jre-temurin17.0.10\"": true,
+				"jdk17":     false,
+				"17.0.10_7": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "dir", Images, "--repository-map=node/*=gofunky/{name}"},
+			stdErr: map[string]bool{
+				"queueing read from directory": true,
+				"the tag command tags the single image 'source', but 3 sources were found; select one with --pattern": true,
+				"docker tag": false,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"tag", "source", "from", "dir", Images, "--pattern=node/*",
+				"--repository-map=node/*=gofunky/{name}"},
+			stdErr: map[string]bool{
+				"queueing read from directory":              true,
+				"docker tag source gofunky/node:1.0-node20": true,
+				"gofunky/git":  false,
+				"golang1.22.3": false,
+			},
+		},
+		{
+			args: []string{"build", "from", "dir", Images, "--repository-map=node/*=gofunky/{name}"},
+			stdOut: map[string]bool{
+				"# ../../test/images/node/Dockerfile": true,
+				"# ../../test/images/git/Dockerfile":  true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "git", "../..", "--tag-prefix=unknown/", "--exclude-branch=*"},
+			stdErr: map[string]bool{
+				"queueing read from git repository":      true,
+				"no semantic version tag could be found": true,
+				"does not yield any tag vectors":         true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"tag", "source", "from", "gomod", GoMod, "--module=github.com/gofunky/*"},
+			stdErr: map[string]bool{
+				"queueing read from go.mod":                    true,
+				"docker tag source automi0.2.0-golang1.23.1\"": true,
+				"golang1.22.6":                                 false,
+				"kong":                                         false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Env, "--merge-go-mod", MinimalGoMod, "--go-alias=go"},
+			stdErr: map[string]bool{
+				"merging sources":     true,
+				"env:alpine-go1.21\"": true,
+				"golang":              false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "_:1.0", "--merge-go-mod", MinimalGoMod},
+			stdErr: map[string]bool{
+				"merging sources":                    true,
+				"docker tag source 1.0-golang1.21\"": true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "pins", ToolVersions, JavaVersion, "--tool=golang=", "--tool=java=openjdk"},
+			stdErr: map[string]bool{
+				"queueing read from pin file":                                 true,
+				"skipping unsupported tool version":                           true,
+				"docker tag source node20.11.0-openjdk17.0.10-python3.12.1\"": true,
+				"golang": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Env, "--merge-pin", JavaVersion},
+			stdErr: map[string]bool{
+				"merging sources":          true,
+				"env:alpine-java17.0.10\"": true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "_:1.0", "--merge-pin", JavaVersion, "--tool=java=openjdk"},
+			stdErr: map[string]bool{
+				"merging sources":                        true,
+				"docker tag source 1.0-openjdk17.0.10\"": true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Env, "--vector", "_:3.0", "--vector", "feature",
+				"--merge-file", Directives},
+			stdErr: map[string]bool{
+				"merging sources":                 true,
+				"ignoring conflicting tag vector": true,
+				"docker tag source gofunky/env:3.0-alpine3-feature-go1.22.3-node20.11.0\"": true,
+				"1.2.0-": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", WithoutRepository, "--merge-file", Conventions,
+				"--vector-arg-prefix=TUPLIP_"},
+			stdErr: map[string]bool{
+				"merging sources": true,
+				"-slim\"":         true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "file", Env, "--vector", "_:3.0", "--merge-file", Directives,
+				"--fail-on-conflict"},
+			stdErr: map[string]bool{
+				"the tag vector '_' has the conflicting versions '3.0' and '1.2.0'": true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"tag", "source", "from", "env", "--env-map=NODE_JS=node"},
+			stdErr: map[string]bool{
+				"queueing read from environment":             true,
+				"docker tag source 1.2.3-node20.11.0-slim\"": true,
+				"node_js": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "bar:${TUPLIP_TEST_BAR}", "--env", "--vector=_:${TUPLIP_TEST_ROOT:-2.0}"},
+			stdErr: map[string]bool{
+				"queueing read from environment":                true,
+				"docker tag source 2.0-bar1.5-node_js20-slim\"": true,
+				"docker tag source 1.2.3":                       false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "bar:${TUPLIP_TEST_UNSET}"},
+			stdErr: map[string]bool{
+				"references the unset variable 'TUPLIP_TEST_UNSET'": true,
+			},
+			wantErr: true,
+		},
+		{
+			args:  []string{"tag", "source", "from", "stdin", "--format=jsonl"},
+			stdin: "{\"version\": \"1.0.0\"}\n{\"alias\": \"alpine\", \"version\": \"3.8.1\", \"options\": {\"exclude-minor\": true}}\n",
+			stdErr: map[string]bool{
+				"queueing read from JSON lines":       true,
+				"docker tag source 1.0-alpine3.8.1\"": true,
+				"alpine3.8\"":                         false,
+			},
+		},
+		{
+			args:  []string{"tag", "source", "from", "stdin", "--format=jsonl"},
+			stdin: "{\"alias\": \"alpine\", \"version\": \"3.8\", \"kind\": \"root\"}\n",
+			stdErr: map[string]bool{
+				"line 1: invalid vector record: alias: must be empty or '_' for root vectors": true,
+			},
+			wantErr: true,
+		},
+		{
+			args:  []string{"tag", "source", "from", "stdin", "--format=nul"},
+			stdin: "foo\x00bar:1.0\x00",
+			stdErr: map[string]bool{
+				"queueing read from NUL-separated reader": true,
+				"docker tag source bar1.0-foo\"":          true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "compose", Compose, "--service=app"},
+			stdErr: map[string]bool{
+				"queueing read from compose file":                                       true,
+				"docker tag source ghcr.io/gofunky/app:1.0.0-alpine3.19-golang1.22.3\"": true,
+				"gofunky/worker": false,
+				"busybox":        false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "compose", Compose, "--service=app", "--service=worker"},
+			stdErr: map[string]bool{
+				"the tag command tags the single image 'source', but 2 sources were found; select one with --service": true,
+				"docker tag source": false,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"push", "source", "to", "gofunky/git", "from", "bake", Bake},
+			stdErr: map[string]bool{
+				"the push command tags the single image 'source', but 2 sources were found; select one with --bake-target": true,
+				"docker push": false,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"push", "from", "compose", Compose, "--service=app", "--service=worker"},
+			stdErr: map[string]bool{
+				"docker push ghcr.io/gofunky/app:1.0.0-alpine3.19-golang1.22.3\"": true,
+				"docker push gofunky/worker:2.1-python3.12.1\"":                   true,
+				"docker tag": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "bake", Bake, "--bake-target=debug", "--build-arg=VERSION=1.1.0"},
+			stdErr: map[string]bool{
+				"queueing read from bake file":          true,
+				"docker tag source 1.1.0-busybox1.36\"": true,
+				"alpine":                                false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "sbom", SPDX, "--os", "--package=pkg:generic/nodejs=node"},
+			stdErr: map[string]bool{
+				"queueing read from SBOM":                      true,
+				"docker tag source alpine3.19.1-node20.11.0\"": true,
+				"openssl": false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "archive", OCILayout, "--platform=linux/amd64", "--distro=alpine=os"},
+			stdErr: map[string]bool{
+				"queueing read from image archive": true,
+				"docker tag source os3.19.1\"":     true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "archive", OCILayout, "--image=2.0.0"},
+			stdErr: map[string]bool{
+				"the archive does not contain the image '2.0.0'": true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"tag", "source", "from", "image", "--image-env=NODE_VERSION=node"},
+			stdErr: map[string]bool{
+				"queueing read from image": true,
+				"\"image\":\"source\"":     true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"build", "from", "image"},
+			stdErr: map[string]bool{
+				"requires an image reference if no source tag is given": true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"library", "--git-commit=abc", "--architecture=amd64", "from", "compose", Compose,
+				"--service=app", "--service=worker"},
+			stdOut: map[string]bool{
+				"Architectures: amd64":          true,
+				"GitCommit: abc":                true,
+				"Directory: test/builds/worker": true,
+				"File: Dockerfile.worker":       true,
+				"SharedTags: 1":                 false,
+			},
+			stdErr: map[string]bool{
+				"building library": true,
+			},
+		},
+		{
+			args: []string{"library", "--base-dir=../../test/builds/app", "from", "compose", Compose,
+				"--service=worker"},
+			stdErr: map[string]bool{
+				"is outside of the base directory": true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"library", "from", "manifest", Manifest, "--image=git"},
+			stdOut: map[string]bool{
+				"Tags: 2.4.1-alpine-foo, 2.4.1-alpine, 2.4.1-foo, 2-alpine-foo, 2.4.1, 2-alpine, 2-foo, alpine-foo, 2, alpine, foo": true,
+			},
+		},
+		{
+			args: []string{"supported", "--link-prefix=https://github.com/gofunky/app/blob/main/", "from", "compose",
+				Compose, "--service=worker"},
+			stdOut: map[string]bool{
+				"- [`2.1-python3.12.1`, `2-python3.12.1`, `2.1-python3.12`, `2-python3.12`, `2.1-python`, " +
+					"`2.1-python3`, `python3.12.1`, `2-python`, `2-python3`, `2.1`, `python3.12`, `2`, `python`, " +
+					"`python3`](https://github.com/gofunky/app/blob/main/test/builds/worker/Dockerfile.worker)": true,
+			},
+			stdErr: map[string]bool{
+				"building library": true,
+			},
+		},
+		{
+			args: []string{"supported", "--markup=html", "from", "library", Library},
+			stdOut: map[string]bool{
+				"<ul>": true,
+				"  <li><code>1.2.0-alpine3.19</code>, <code>1.2-alpine3.19</code>, <code>1-alpine3.19</code>, " +
+					"<code>alpine3.19</code>, <code>alpine</code></li>": true,
+				"</ul>": true,
+			},
+		},
+		{
+			args: []string{"supported", "--base-dir=../../test", "--link-prefix=https://github.com/gofunky/app/blob/main/",
+				"from", "compose", Compose, "--service=worker"},
+			stdOut: map[string]bool{
+				"- [`2.1-python3.12.1`, `2-python3.12.1`, `2.1-python3.12`, `2-python3.12`, `2.1-python`, " +
+					"`2.1-python3`, `python3.12.1`, `2-python`, `2-python3`, `2.1`, `python3.12`, `2`, `python`, " +
+					"`python3`](https://github.com/gofunky/app/blob/main/builds/worker/Dockerfile.worker)": true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "library", Library},
+			stdErr: map[string]bool{
+				"the tag command tags the single image 'source', but 2 sources were found; select one with --directory": true,
+				"docker tag": false,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"tag", "source", "from", "library", Library, "--directory=1.2/bookworm"},
+			stdErr: map[string]bool{
+				"queueing read from library file":        true,
+				"straight channel enabled":               true,
+				"docker tag source app:1.2.0-bookworm\"": true,
+				"docker tag source app:latest\"":         true,
+				"docker tag source app:alpine\"":         false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "_:1.2", "alpine", "--output=csv"},
+			stdOut: map[string]bool{
+				"repository,tag,vectors,specificity": true,
+				",1.2-alpine,1.2 alpine,3":           true,
+				",1-alpine,1 alpine,2":               true,
+				"1.2-alpine":                         false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "to", "gofunky/git", "from", "_:1.2", "alpine", "--output=json"},
+			stdOut: map[string]bool{
+				"    \"repository\": \"gofunky/git\",": true,
+				"    \"tag\": \"1.2-alpine\",":         true,
+				"    \"specificity\": 3":               true,
+			},
+		},
+		{
+			args: []string{"push", "source", "to", "gofunky/git", "from", "_:1.2", "alpine", "--ci=gitlab",
+				"--ci-dotenv=" + dotenv},
+			stdErr: map[string]bool{
+				"writing CI output":       true,
+				"docker push gofunky/git": true,
+			},
+			stdOut: map[string]bool{
+				"gofunky/git:1.2-alpine": true,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "ci", "--vector=alpine", "--ci=auto"},
+			stdErr: map[string]bool{
+				"queueing read from CI environment": true,
+				"\"platform\":\"dockerhub\"":        true,
+				"writing CI output":                 false,
+			},
+			stdOut: map[string]bool{
+				"alpine-edge": true,
+				"master":      false,
+			},
+		},
+		{
+			args: []string{"tag", "source", "from", "ci", "--ci-channel=master=", "--ci-channel=*=dev"},
+			stdErr: map[string]bool{
+				"no tag vectors could be found in the CI environment": true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"tag", "source", "from", "foo", "--ci=github"},
+			stdErr: map[string]bool{
+				"require the environment variable GITHUB_OUTPUT": true,
+			},
+			wantErr: true,
+		},
+		{
+			args: []string{"supported", "from", "_:1.2", "alpine", "--output=yaml"},
+			stdErr: map[string]bool{
+				"the supported command does not support the output format 'yaml'": true,
+			},
+			wantErr: true,
+		},
 		{
 			args: []string{"tag", "source", "from", "foo", "goo"},
 			stdErr: map[string]bool{
@@ -309,6 +793,18 @@ func TestBuild(t *testing.T) {
 				"docker push gofunky/ignore:foo-golang":       true,
 			},
 		},
+		{
+			args: []string{"push", "source", "from", "manifest", Manifest, "--image=git"},
+			stdErr: map[string]bool{
+				"queueing read from manifest":                  true,
+				"queueing tagging":                             true,
+				"queueing push":                                true,
+				"docker tag source gofunky/git:2.4.1-foo":      true,
+				"docker push gofunky/git:2.4.1-alpine-foo":     true,
+				"docker push gofunky/ignore:2.4.1-alpine-foo":  true,
+				"docker push gofunky/ignore:2.4-alpine3.8-foo": false,
+			},
+		},
 		{
 			args: []string{"push", "source", "from", "foo", "goo"},
 			stdErr: map[string]bool{
@@ -345,6 +841,15 @@ func TestBuild(t *testing.T) {
 			},
 		},
 	}
+	t.Setenv("TUPLIP_ROOT", "1.2.3")
+	t.Setenv("TUPLIP_VECTOR_NODE_JS", "20.11.0")
+	t.Setenv("TUPLIP_ALIAS", "slim")
+	t.Setenv("TUPLIP_TEST_BAR", "1.5")
+	t.Setenv("GITHUB_OUTPUT", "")
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("GITLAB_CI", "")
+	t.Setenv("SOURCE_BRANCH", "master")
+	t.Setenv("DOCKER_TAG", "latest")
 	for _, rawTT := range tests {
 		for _, mod := range matrix {
 			rawCommand := strings.Join(rawTT.args, " ")
@@ -422,3 +927,66 @@ func TestBuild(t *testing.T) {
 		}
 	}
 }
+
+func TestSupported_Readme(t *testing.T) {
+	const section = "<!-- tuplip:supported-tags -->\n\n" +
+		"- `1.2.0-alpine3.19`, `1.2-alpine3.19`, `1-alpine3.19`, `alpine3.19`, `alpine`\n" +
+		"- `1.2.0-bookworm`, `1.2-bookworm`, `1.2.0`, `1-bookworm`, `1.2`, `1`, `bookworm`, `latest`\n\n" +
+		"<!-- tuplip:supported-tags-end -->"
+	tests := []struct {
+		name     string
+		content  string
+		simulate bool
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "Replace Section",
+			content: "# App\n\n<!-- tuplip:supported-tags -->\n- `0.9`\n<!-- tuplip:supported-tags-end -->\n\n" +
+				"## Usage\n",
+			want: "# App\n\n" + section + "\n\n## Usage\n",
+		},
+		{
+			name:     "Simulation",
+			content:  "# App\n\n<!-- tuplip:supported-tags -->\n- `0.9`\n<!-- tuplip:supported-tags-end -->\n",
+			simulate: true,
+			want:     "# App\n\n<!-- tuplip:supported-tags -->\n- `0.9`\n<!-- tuplip:supported-tags-end -->\n",
+		},
+		{
+			name:    "Missing Markers",
+			content: "# App\n\n## Supported tags\n",
+			want:    "# App\n\n## Supported tags\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readme := filepath.Join(t.TempDir(), "README.md")
+			if err := os.WriteFile(readme, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cliArgs := []string{"supported", "--readme=" + readme, "from", "library", Library, "--verbose"}
+			if tt.simulate {
+				cliArgs = append(cliArgs, "--simulate")
+			}
+			c := testcli.Command("tuplip", cliArgs...)
+			c.Run()
+			if c.Success() == tt.wantErr {
+				t.Fatalf("tuplip error = %v, wantErr %v, error message:\n%v", c.Success(), tt.wantErr, c.Stderr())
+			}
+			if tt.wantErr && !c.StderrContains("has no section between the markers") {
+				t.Errorf("Expected the missing markers in stderr:\n%v", c.Stderr())
+			}
+			if c.Stdout() != "" {
+				t.Errorf("Expected no stdout, got:\n%v", c.Stdout())
+			}
+			got, err := os.ReadFile(readme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("README = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
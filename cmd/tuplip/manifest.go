@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+)
+
+// manifestOption defines a command branch that contains only the manifest command.
+type manifestOption struct {
+	// Manifest to read the images and their tag vectors from a tuplip manifest.
+	Manifest manifestCmd `cmd:"" help:"read the images and their tag vectors from a tuplip manifest"`
+}
+
+// manifestCmd defines a command to read tag vectors from a tuplip manifest.
+type manifestCmd struct {
+	Context tuplipContext `embed:""`
+	// File is the tuplip manifest in YAML or JSON format.
+	File string `arg:"" optional:"" help:"the tuplip manifest in YAML or JSON format (default: tuplip.yaml, tuplip.yml, or tuplip.json)"`
+	// Image limits the processing to the given images of the manifest.
+	Image []string `help:"only process the images with the given names from the manifest"`
+}
+
+// Run implements a dynamic interface from kong by executing a command for each image in the given manifest.
+func (c manifestCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	sources, err := (&tuplip).FromManifest(c.File, c.Image)
+	if err != nil {
+		return err
+	}
+	if err = c.Context.requireSingleSource(ctx, sources, "--image"); err != nil {
+		return err
+	}
+	return c.Context.toRoots(ctx, sources)
+}
@@ -19,7 +19,7 @@ type paramCmd struct {
 
 // Run implements a dynamic interface from kong by executing a command using given param argument as input.
 func (c paramCmd) Run(ctx *kong.Context) error {
-	tuplip := c.Context.Tuplip
+	tuplip := c.Context.tuplip()
 	src := (&tuplip).FromSlice(c.Param)
 	return c.Context.toRoot(ctx, src)
 }
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+)
+
+// pinsOption defines a command branch that contains only the pins command.
+type pinsOption struct {
+	// Pins to read the tag vectors from runtime pin files.
+	Pins pinsCmd `cmd:"" help:"read the tool versions from runtime pin files such as .tool-versions, .nvmrc, .python-version, or .java-version"`
+}
+
+// pinsCmd defines a command to read tag vectors from runtime pin files.
+// The tool mapping is taken from the merge flags of the context.
+type pinsCmd struct {
+	Context tuplipContext `embed:""`
+	// Files are the runtime pin files.
+	Files []string `arg:"" optional:"" type:"existingfile" help:"the runtime pin files (default: the known pin files in the working directory)"`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the given pin files as input.
+func (c pinsCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromPins(c.Files, c.Context.Merge.PinOptions); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
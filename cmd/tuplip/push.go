@@ -37,10 +37,15 @@ func (s pushCmd) run(src *tupliplib.TuplipSource) (stream *stream.Stream, err er
 	if err != nil {
 		return nil, err
 	}
-	if !cli.Verbose {
-		stream.Filter(func(in string) bool {
-			return false
-		})
-	}
 	return
 }
+
+// silent implements main.silentCmd.silent by printing the pushed Docker tags only in verbose mode.
+func (s pushCmd) silent() bool {
+	return !cli.Verbose
+}
+
+// sourceImage implements main.sourceImageCmd.sourceImage by returning the source tag.
+func (s pushCmd) sourceImage() string {
+	return s.sourceTagOption.SourceTag.SourceTag
+}
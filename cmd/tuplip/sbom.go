@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// sbomOption defines a command branch that contains only the sbom command.
+type sbomOption struct {
+	// SBOM to read the tag vectors from a software bill of materials.
+	SBOM sbomCmd `cmd:"" name:"sbom" help:"read the package versions from an SPDX or CycloneDX JSON document"`
+}
+
+// sbomCmd defines a command to read tag vectors from a software bill of materials.
+type sbomCmd struct {
+	Context tuplipContext `embed:""`
+	// File is the SBOM file.
+	File string `arg:"" type:"existingfile" help:"the SPDX or CycloneDX JSON document"`
+	// Options contain the parameters for reading the SBOM.
+	Options tupliplib.SBOMOptions `embed:""`
+}
+
+// Run implements a dynamic interface from kong by executing a command using the given SBOM as input.
+func (c sbomCmd) Run(ctx *kong.Context) error {
+	tuplip := c.Context.tuplip()
+	if src, err := (&tuplip).FromSBOM(c.File, c.Options); err != nil {
+		return err
+	} else {
+		return c.Context.toRoot(ctx, src)
+	}
+}
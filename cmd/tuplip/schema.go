@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// schemaCmd contains the options for the schema command.
+type schemaCmd struct{}
+
+// Run prints the JSON schema of the tuplip manifest.
+func (s *schemaCmd) Run(ctx *kong.Context) error {
+	_, err := fmt.Print(tupliplib.ManifestSchema)
+	return err
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"github.com/alecthomas/kong"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
 	"os"
 )
 
@@ -17,12 +18,24 @@ type stdinCmd struct {
 	Context tuplipContext `embed:""`
 	// Separator that splits the separate tag vectors. The default separator is a single space.
 	Separator string `optional:"" env:"IFS" default:" " help:"the separator that splits the separate tag vectors from the stdin"`
+	// Format is the format of the tag vectors in the stdin.
+	Format tupliplib.InputFormat `enum:"text,jsonl,nul" default:"text" help:"the format of the tag vectors from the stdin (text, jsonl, or nul)"`
 }
 
 // Run implements a dynamic interface from kong by executing a command using the stdin as input.
 func (c stdinCmd) Run(ctx *kong.Context) error {
 	reader := bufio.NewReader(os.Stdin)
-	tuplip := c.Context.Tuplip
-	src := (&tuplip).FromReader(reader, c.Separator)
-	return c.Context.toRoot(ctx, src)
+	tuplip := c.Context.tuplip()
+	switch c.Format {
+	case tupliplib.JSONLinesInput:
+		src, err := (&tuplip).FromJSONLines(reader)
+		if err != nil {
+			return err
+		}
+		return c.Context.toRoot(ctx, src)
+	case tupliplib.NULInput:
+		return c.Context.toRoot(ctx, (&tuplip).FromNUL(reader))
+	default:
+		return c.Context.toRoot(ctx, (&tuplip).FromReader(reader, c.Separator))
+	}
 }
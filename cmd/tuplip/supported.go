@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+	"github.com/gofunky/tuplip/pkg/tupliplib"
+)
+
+// supportedCmd contains the options for the supported command.
+type supportedCmd struct {
+	// Options contain the parameters for rendering the section.
+	Options tupliplib.SupportedTagsOptions `embed:""`
+	// Readme is the README file whose supported tags section is replaced.
+	Readme string `type:"existingfile" placeholder:"FILE" help:"replace the section between the supported tags markers of the given README file instead of printing it"`
+	// BaseDir is the directory that the links of the Dockerfiles are relative to.
+	BaseDir string `type:"existingdir" placeholder:"DIR" help:"the directory that the directories of the Dockerfiles are relative to (default: the top level of their git repository)"`
+	// From command determines the source of the tag vectors.
+	From sourceOption `cmd:"" help:"determine the source of the tag vectors"`
+}
+
+// runAll implements main.batchCmd.runAll by rendering the supported tags of the given sources with one item per
+// source.
+func (s supportedCmd) runAll(tuplip *tupliplib.Tuplip, sources []*tupliplib.TuplipSource) (*stream.Stream, error) {
+	library, err := tupliplib.BuildLibrary(tupliplib.LibraryEntry{}, s.BaseDir, sources...)
+	if err != nil {
+		return nil, err
+	}
+	section := library.SupportedTags(s.Options)
+	if s.Readme != "" {
+		if err = tuplip.ReplaceSupportedTags(s.Readme, section); err != nil {
+			return nil, err
+		}
+		return stream.New(emitters.Slice([]string{})), nil
+	}
+	lines := strings.Split(strings.TrimSuffix(section, "\n"), "\n")
+	return stream.New(emitters.Slice(lines)), nil
+}
@@ -25,10 +25,15 @@ func (s tagCmd) run(src *tupliplib.TuplipSource) (stream *stream.Stream, err err
 	if err != nil {
 		return nil, err
 	}
-	if !cli.Verbose {
-		stream.Filter(func(in string) bool {
-			return false
-		})
-	}
 	return
 }
+
+// silent implements main.silentCmd.silent by printing the tagged Docker tags only in verbose mode.
+func (s tagCmd) silent() bool {
+	return !cli.Verbose
+}
+
+// sourceImage implements main.sourceImageCmd.sourceImage by returning the source tag.
+func (s tagCmd) sourceImage() string {
+	return s.sourceTagOption.SourceTag.SourceTag
+}
@@ -7,22 +7,29 @@ toolchain go1.23.1
 require (
 	github.com/alecthomas/kong v0.9.0
 	github.com/blang/semver/v4 v4.0.0
+	github.com/distribution/reference v0.6.0
 	github.com/emicklei/dot v1.6.2
 	github.com/francoispqt/onelog v0.0.0-20190306043706-8c2bb31b10a4
 	github.com/go-ozzo/ozzo-validation/v4 v4.3.0
 	github.com/gofunky/automi v0.3.5
 	github.com/gofunky/pyraset/v2 v2.0.5
 	github.com/google/go-cmp v0.6.0
+	github.com/hashicorp/hcl/v2 v2.22.0
 	github.com/nokia/docker-registry-client v0.0.0-20201015093031-af1a6d3b4fb1
 	github.com/oleiade/reflections v1.1.0
 	github.com/rendon/testcli v1.0.0
+	github.com/zclconf/go-cty v1.13.2
 	go.uber.org/atomic v1.11.0
+	golang.org/x/mod v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/OneOfOne/xxhash v1.2.8 // indirect
+	github.com/agext/levenshtein v1.2.1 // indirect
+	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
+	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
 	github.com/asaskevich/govalidator v0.0.0-20200819183940-29e1ff8eb0bb // indirect
-	github.com/distribution/reference v0.6.0 // indirect
 	github.com/docker/distribution v2.8.3+incompatible // indirect
 	github.com/docker/libtrust v0.0.0-20160708172513-aabc10ec26b7 // indirect
 	github.com/emirpasic/gods v1.18.1 // indirect
@@ -30,8 +37,10 @@ require (
 	github.com/go-faces/logger v0.0.0-20180617163310-c221c1151623 // indirect
 	github.com/gofunky/hashstructure v1.3.0 // indirect
 	github.com/gorilla/mux v1.7.4 // indirect
+	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	github.com/opencontainers/go-digest v1.0.0 // indirect
 	github.com/opencontainers/image-spec v1.1.0 // indirect
 	github.com/sirupsen/logrus v1.9.3 // indirect
 	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
 )
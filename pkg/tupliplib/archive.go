@@ -0,0 +1,423 @@
+package tupliplib
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+const (
+	// DockerArchiveManifest is the manifest of a `docker save` archive.
+	DockerArchiveManifest = "manifest.json"
+	// OCILayoutIndex is the index of an OCI image layout.
+	OCILayoutIndex = "index.json"
+	// OSReleaseFile is the file that identifies the operating system of an image.
+	OSReleaseFile = "etc/os-release"
+	// OSReleaseFallback is the file that is used if the image has no OSReleaseFile.
+	OSReleaseFallback = "usr/lib/os-release"
+	// ociRefNameAnnotation is the OCI annotation that contains the reference of an image in an image layout.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+	// containerdImageNameAnnotation is the annotation that contains the full image name in `docker save` archives.
+	containerdImageNameAnnotation = "io.containerd.image.name"
+	// whiteoutPrefix marks the files that are deleted in a layer.
+	whiteoutPrefix = ".wh."
+	// opaqueWhiteout marks the directories whose contents of the lower layers are deleted.
+	opaqueWhiteout = whiteoutPrefix + whiteoutPrefix + ".opq"
+	// maxOSReleaseSize limits the size of the read os-release files.
+	maxOSReleaseSize = 64 * 1024
+)
+
+// DistroAliases map the os-release IDs to the vector aliases that differ from the normalized ID.
+// The aliases follow the names of the official images.
+var DistroAliases = map[string]string{
+	"amzn":          "amazonlinux",
+	"ol":            "oraclelinux",
+	"opensuse-leap": "opensuse",
+	"rocky":         "rockylinux",
+}
+
+// ArchiveOptions contain the parameters for reading tag vectors from an image archive.
+type ArchiveOptions struct {
+	// Image selects the image in an archive with multiple images by one of its references.
+	Image string `placeholder:"REFERENCE" help:"the reference of the image in archives with multiple images (default: the first image)"`
+	// Platform selects the image of a multi-platform image in the format `os/arch[/variant]`.
+	Platform string `placeholder:"OS/ARCH[/VARIANT]" help:"the platform of multi-platform images (default: the first platform)"`
+	// DistroMap maps the os-release IDs to vector aliases.
+	// IDs without mapping use the DistroAliases or their normalized ID.
+	DistroMap []string `name:"distro" placeholder:"ID=ALIAS" help:"map the os-release ID of the distribution to a vector alias"`
+}
+
+// FromArchive builds a tuplip source from an image archive without a Docker engine.
+// The archive is either a tarball created by `docker save` or an OCI image layout directory or tarball.
+// The distribution of the image yields a dependency vector with the ID and the VERSION_ID of its os-release file
+// (e.g., `alpine:3.19.1` or `debian:12`).
+func (t *Tuplip) FromArchive(src string, options ArchiveOptions) (source *TuplipSource, err error) {
+	logger.InfoWith("queueing read from image archive").
+		String("archive", src).
+		Write()
+	mapping, err := parseAliasMap(options.DistroMap, "ID=ALIAS")
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	var store imageStore = tarStore(src)
+	if info.IsDir() {
+		store = directoryStore(src)
+	}
+	layers, err := imageLayers(store, options)
+	if err != nil {
+		return nil, fmt.Errorf("the image archive '%s' could not be read: %v", src, err)
+	}
+	content, err := findOSRelease(store, layers)
+	if err != nil {
+		return nil, fmt.Errorf("the image archive '%s' could not be read: %v", src, err)
+	}
+	release := parseOSRelease(content)
+	id, version := release["ID"], vectorVersion(release["VERSION_ID"])
+	if id == "" || version == "" || !unicode.IsDigit(rune(version[0])) {
+		return nil, fmt.Errorf("the os-release of the image in '%s' has no supported ID and VERSION_ID", src)
+	}
+	alias, ok := mapping[id]
+	if !ok {
+		if alias, ok = DistroAliases[id]; !ok {
+			alias = normalizeAlias(id)
+		}
+	}
+	if alias == "" {
+		return nil, fmt.Errorf("the image archive '%s' does not yield any tag vectors", src)
+	}
+	logger.InfoWith("found distribution").
+		String("id", id).
+		String("version", version).
+		Write()
+	stm := stream.New(emitters.Slice([]string{alias + VersionSeparator + version}))
+	return &TuplipSource{tuplip: t, stream: stm}, nil
+}
+
+// imageStore provides the files of an image archive by their slash-separated paths.
+type imageStore interface {
+	// open opens the file with the given path. The error wraps fs.ErrNotExist if the file does not exist.
+	open(name string) (io.ReadCloser, error)
+}
+
+// directoryStore is an image store of an unpacked image archive.
+type directoryStore string
+
+// open opens the file with the given path in the directory.
+func (d directoryStore) open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(string(d), filepath.FromSlash(name)))
+}
+
+// tarStore is an image store of an image archive tarball.
+type tarStore string
+
+// open finds the file with the given path in the tarball.
+func (s tarStore) open(name string) (io.ReadCloser, error) {
+	file, err := os.Open(string(s))
+	if err != nil {
+		return nil, err
+	}
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			file.Close()
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		} else if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if path.Clean(header.Name) == name {
+			return struct {
+				io.Reader
+				io.Closer
+			}{reader, file}, nil
+		}
+	}
+}
+
+// readJSON decodes the JSON file with the given path of the given image store into the given value.
+func readJSON(store imageStore, name string, value interface{}) error {
+	reader, err := store.open(name)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err = json.NewDecoder(reader).Decode(value); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+// dockerArchiveImage is an image entry of the DockerArchiveManifest.
+type dockerArchiveImage struct {
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// ociPlatform is the platform of an OCI descriptor.
+type ociPlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant"`
+}
+
+// ociDescriptor is an OCI content descriptor.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Platform    *ociPlatform      `json:"platform"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// ociIndex is an OCI image index or a Docker manifest list.
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// ociManifest is an OCI image manifest or a Docker image manifest.
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// blob returns the path of the blob of the descriptor in an OCI image layout.
+func (d ociDescriptor) blob() (string, error) {
+	algorithm, hash, ok := strings.Cut(d.Digest, ":")
+	if !ok || algorithm == "" || hash == "" || strings.ContainsAny(d.Digest, "/\\") {
+		return "", fmt.Errorf("invalid digest '%s'", d.Digest)
+	}
+	return path.Join("blobs", algorithm, hash), nil
+}
+
+// matches checks if the descriptor has the given platform in the format `os/arch[/variant]`.
+// Descriptors without platform match any platform. Descriptors of the `unknown` platform, such as attestations,
+// never match. An empty platform matches all other descriptors.
+func (d ociDescriptor) matches(platform string) bool {
+	if d.Platform == nil {
+		return true
+	}
+	if d.Platform.OS == "unknown" {
+		return false
+	}
+	if platform == "" {
+		return true
+	}
+	base := d.Platform.OS + "/" + d.Platform.Architecture
+	return platform == base || platform == base+"/"+d.Platform.Variant
+}
+
+// imageLayers returns the paths of the layers of the selected image in the given image store from the bottom to
+// the top layer.
+func imageLayers(store imageStore, options ArchiveOptions) (layers []string, err error) {
+	var images []dockerArchiveImage
+	if err = readJSON(store, DockerArchiveManifest, &images); err == nil {
+		for _, image := range images {
+			if options.Image == "" || containsString(image.RepoTags, options.Image) {
+				return image.Layers, nil
+			}
+		}
+		return nil, fmt.Errorf("the archive does not contain the image '%s'", options.Image)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	var index ociIndex
+	if err = readJSON(store, OCILayoutIndex, &index); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("neither a docker save archive nor an OCI image layout")
+	} else if err != nil {
+		return nil, err
+	}
+	var descriptors []ociDescriptor
+	for _, descriptor := range index.Manifests {
+		if options.Image == "" || descriptor.Annotations[ociRefNameAnnotation] == options.Image ||
+			descriptor.Annotations[containerdImageNameAnnotation] == options.Image {
+			descriptors = append(descriptors, descriptor)
+		}
+	}
+	if len(descriptors) == 0 {
+		return nil, fmt.Errorf("the archive does not contain the image '%s'", options.Image)
+	}
+	if layers, err = ociLayers(store, descriptors, options.Platform); err != nil {
+		return nil, err
+	} else if layers == nil {
+		return nil, fmt.Errorf("the archive does not contain an image for the platform '%s'", options.Platform)
+	}
+	return layers, nil
+}
+
+// ociLayers returns the blob paths of the layers of the first image manifest that matches the given platform.
+// Nested indexes are resolved. The layers are nil if no image manifest matches.
+func ociLayers(store imageStore, descriptors []ociDescriptor, platform string) (layers []string, err error) {
+	for _, descriptor := range descriptors {
+		if !descriptor.matches(platform) {
+			continue
+		}
+		blob, err := descriptor.blob()
+		if err != nil {
+			return nil, err
+		}
+		var content struct {
+			ociIndex
+			ociManifest
+		}
+		if err = readJSON(store, blob, &content); err != nil {
+			return nil, err
+		}
+		if len(content.Manifests) > 0 {
+			if layers, err = ociLayers(store, content.Manifests, platform); err != nil || layers != nil {
+				return layers, err
+			}
+			continue
+		}
+		for _, layer := range content.Layers {
+			layerBlob, err := layer.blob()
+			if err != nil {
+				return nil, err
+			}
+			layers = append(layers, layerBlob)
+		}
+		if layers == nil {
+			layers = []string{}
+		}
+		return layers, nil
+	}
+	return nil, nil
+}
+
+// layerEntry is the state of a file in a layer.
+type layerEntry struct {
+	// content is the content of a regular file.
+	content []byte
+	// regular is set if the file is a regular file. Otherwise, it is a link or deleted.
+	regular bool
+}
+
+// findOSRelease reads the OSReleaseFile of the given image layers, or the OSReleaseFallback if the image has no
+// OSReleaseFile or if it is a link. The layers are searched from the top to the bottom while respecting whiteouts.
+func findOSRelease(store imageStore, layers []string) ([]byte, error) {
+	found := make(map[string]layerEntry)
+	for i := len(layers) - 1; i >= 0; i-- {
+		entries, err := readLayer(store, layers[i], OSReleaseFile, OSReleaseFallback)
+		if err != nil {
+			return nil, err
+		}
+		for name, entry := range entries {
+			if _, ok := found[name]; !ok {
+				found[name] = entry
+			}
+		}
+		entry, ok := found[OSReleaseFile]
+		if ok && entry.regular {
+			return entry.content, nil
+		} else if _, fallback := found[OSReleaseFallback]; ok && fallback {
+			break
+		}
+	}
+	if entry, ok := found[OSReleaseFallback]; ok && entry.regular {
+		return entry.content, nil
+	}
+	return nil, fmt.Errorf("the image has no os-release file")
+}
+
+// readLayer reads the entries of the given files from the given layer blob.
+// The layer is an uncompressed or a gzip-compressed tarball.
+func readLayer(store imageStore, layer string, names ...string) (entries map[string]layerEntry, err error) {
+	blob, err := store.open(layer)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+	buffered := bufio.NewReader(blob)
+	var reader io.Reader = buffered
+	if magic, _ := buffered.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", layer, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	entries = make(map[string]layerEntry)
+	var opaque []string
+	layerReader := tar.NewReader(reader)
+	for {
+		header, err := layerReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %v", layer, err)
+		}
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		dir, base := path.Split(name)
+		if base == opaqueWhiteout {
+			opaque = append(opaque, dir)
+			continue
+		}
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			name = dir + strings.TrimPrefix(base, whiteoutPrefix)
+			if containsString(names, name) {
+				entries[name] = layerEntry{}
+			}
+			continue
+		}
+		if !containsString(names, name) {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			entries[name] = layerEntry{}
+			continue
+		}
+		content, err := io.ReadAll(io.LimitReader(layerReader, maxOSReleaseSize))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", layer, err)
+		}
+		entries[name] = layerEntry{content: content, regular: true}
+	}
+	for _, name := range names {
+		if _, ok := entries[name]; ok {
+			continue
+		}
+		for _, dir := range opaque {
+			if strings.HasPrefix(name, dir) {
+				entries[name] = layerEntry{}
+			}
+		}
+	}
+	return entries, nil
+}
+
+// parseOSRelease parses the variable assignments of the given os-release file.
+// Comments and invalid lines are skipped.
+func parseOSRelease(content []byte) map[string]string {
+	release := make(map[string]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, "'")
+		}
+		release[key] = value
+	}
+	return release
+}
@@ -0,0 +1,309 @@
+package tupliplib
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+// testEntry is a file of a test layer. Symlinks have their target as content.
+type testEntry struct {
+	name     string
+	content  string
+	typeflag byte
+}
+
+// osRelease returns a regular os-release test entry at the given path.
+func osRelease(name, id, version string) testEntry {
+	return testEntry{
+		name:     name,
+		content:  "NAME=\"Test Linux\"\nID=" + id + "\nVERSION_ID=\"" + version + "\"\n",
+		typeflag: tar.TypeReg,
+	}
+}
+
+// tarball creates a tarball of the given entries.
+func tarball(t *testing.T, entries ...testEntry) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Typeflag: entry.typeflag, Mode: 0644}
+		if entry.typeflag == tar.TypeSymlink {
+			header.Linkname = entry.content
+		} else if entry.typeflag == tar.TypeReg {
+			header.Size = int64(len(entry.content))
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(entry.content)[:header.Size]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// gzipped compresses the given data.
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// writeDockerArchive writes a `docker save` tarball with the given images of layers to a temporary file.
+func writeDockerArchive(t *testing.T, images map[string][][]byte) string {
+	t.Helper()
+	var manifest []dockerArchiveImage
+	var entries []testEntry
+	for tag, layers := range images {
+		image := dockerArchiveImage{RepoTags: []string{tag}}
+		for i, layer := range layers {
+			name := filepath.ToSlash(filepath.Join(normalizeAlias(tag), string(rune('a'+i)), "layer.tar"))
+			image.Layers = append(image.Layers, name)
+			entries = append(entries, testEntry{name: name, content: string(layer), typeflag: tar.TypeReg})
+		}
+		manifest = append(manifest, image)
+	}
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries = append(entries, testEntry{name: DockerArchiveManifest, content: string(content), typeflag: tar.TypeReg})
+	file := filepath.Join(t.TempDir(), "image.tar")
+	if err = os.WriteFile(file, tarball(t, entries...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// writeBlob writes the given content as blob of the OCI image layout in the given directory.
+func writeBlob(t *testing.T, dir string, mediaType string, content []byte) ociDescriptor {
+	t.Helper()
+	hash := sha256.Sum256(content)
+	descriptor := ociDescriptor{MediaType: mediaType, Digest: "sha256:" + hex.EncodeToString(hash[:])}
+	blob, err := descriptor.blob()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, blob), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return descriptor
+}
+
+// writeJSONBlob writes the given value as JSON blob of the OCI image layout in the given directory.
+func writeJSONBlob(t *testing.T, dir string, mediaType string, value interface{}) ociDescriptor {
+	t.Helper()
+	content, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeBlob(t, dir, mediaType, content)
+}
+
+// writeOCILayout writes a multi-platform OCI image layout with an attestation manifest to a temporary directory.
+// The images of the platforms have a single gzip-compressed layer.
+func writeOCILayout(t *testing.T, platforms map[ociPlatform][]byte) string {
+	t.Helper()
+	dir := t.TempDir()
+	attestation := writeJSONBlob(t, dir, "application/vnd.oci.image.manifest.v1+json", ociManifest{})
+	attestation.Platform = &ociPlatform{OS: "unknown", Architecture: "unknown"}
+	platformIndex := ociIndex{Manifests: []ociDescriptor{attestation}}
+	for platform, layer := range platforms {
+		layerDescriptor := writeBlob(t, dir, "application/vnd.oci.image.layer.v1.tar+gzip", gzipped(t, layer))
+		manifest := writeJSONBlob(t, dir, "application/vnd.oci.image.manifest.v1+json",
+			ociManifest{Layers: []ociDescriptor{layerDescriptor}})
+		manifest.Platform = &ociPlatform{OS: platform.OS, Architecture: platform.Architecture, Variant: platform.Variant}
+		platformIndex.Manifests = append(platformIndex.Manifests, manifest)
+	}
+	descriptor := writeJSONBlob(t, dir, "application/vnd.oci.image.index.v1+json", platformIndex)
+	descriptor.Annotations = map[string]string{ociRefNameAnnotation: "1.0.0"}
+	content, err := json.Marshal(ociIndex{Manifests: []ociDescriptor{descriptor}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, OCILayoutIndex), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestTuplip_FromArchive(t *testing.T) {
+	alpine := tarball(t, testEntry{name: "etc/", typeflag: tar.TypeDir}, osRelease("etc/os-release", "alpine", "3.19.1"))
+	debian := tarball(t,
+		testEntry{name: "./etc/os-release", content: "../usr/lib/os-release", typeflag: tar.TypeSymlink},
+		osRelease("./usr/lib/os-release", "debian", "12"),
+	)
+	tests := []struct {
+		name        string
+		archive     func(t *testing.T) string
+		options     ArchiveOptions
+		wantVectors []interface{}
+		wantErr     bool
+	}{
+		{
+			name: "Docker Archive",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {alpine, tarball(t, testEntry{name: "app", content: "app", typeflag: tar.TypeReg})},
+				})
+			},
+			wantVectors: []interface{}{"alpine:3.19.1"},
+		},
+		{
+			name: "Upgraded Distribution",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {alpine, tarball(t, osRelease("etc/os-release", "alpine", "3.19.2"))},
+				})
+			},
+			wantVectors: []interface{}{"alpine:3.19.2"},
+		},
+		{
+			name: "Symlink",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {debian, tarball(t, osRelease("usr/lib/os-release", "debian", "12.5"))},
+				})
+			},
+			wantVectors: []interface{}{"debian:12.5"},
+		},
+		{
+			name: "Whiteout",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {alpine, tarball(t, testEntry{name: "etc/.wh.os-release", typeflag: tar.TypeReg})},
+				})
+			},
+			wantErr: true,
+		},
+		{
+			name: "Opaque Directory",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {debian, tarball(t,
+						testEntry{name: "usr/lib/.wh..wh..opq", typeflag: tar.TypeReg},
+						testEntry{name: "usr/lib/app", content: "app", typeflag: tar.TypeReg},
+					)},
+				})
+			},
+			wantErr: true,
+		},
+		{
+			name: "Selected Image",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0":    {alpine},
+					"gofunky/app:1.0.0-db": {debian},
+				})
+			},
+			options:     ArchiveOptions{Image: "gofunky/app:1.0.0-db"},
+			wantVectors: []interface{}{"debian:12"},
+		},
+		{
+			name: "Distribution Alias",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {tarball(t, osRelease("etc/os-release", "amzn", "2023"))},
+				})
+			},
+			wantVectors: []interface{}{"amazonlinux:2023"},
+		},
+		{
+			name: "Distribution Mapping",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {tarball(t, osRelease("etc/os-release", "amzn", "2023"))},
+				})
+			},
+			options:     ArchiveOptions{DistroMap: []string{"amzn=al"}},
+			wantVectors: []interface{}{"al:2023"},
+		},
+		{
+			name: "Unversioned Distribution",
+			archive: func(t *testing.T) string {
+				return writeDockerArchive(t, map[string][][]byte{
+					"gofunky/app:1.0.0": {tarball(t, testEntry{name: "etc/os-release", content: "ID=debian\n", typeflag: tar.TypeReg})},
+				})
+			},
+			wantErr: true,
+		},
+		{
+			name: "OCI Layout",
+			archive: func(t *testing.T) string {
+				return writeOCILayout(t, map[ociPlatform][]byte{{OS: "linux", Architecture: "amd64"}: alpine})
+			},
+			wantVectors: []interface{}{"alpine:3.19.1"},
+		},
+		{
+			name: "OCI Layout Platform",
+			archive: func(t *testing.T) string {
+				return writeOCILayout(t, map[ociPlatform][]byte{
+					{OS: "linux", Architecture: "amd64"}:                alpine,
+					{OS: "linux", Architecture: "arm64", Variant: "v8"}: debian,
+				})
+			},
+			options:     ArchiveOptions{Platform: "linux/arm64", Image: "1.0.0"},
+			wantVectors: []interface{}{"debian:12"},
+		},
+		{
+			name: "OCI Layout Missing Platform",
+			archive: func(t *testing.T) string {
+				return writeOCILayout(t, map[ociPlatform][]byte{{OS: "linux", Architecture: "amd64"}: alpine})
+			},
+			options: ArchiveOptions{Platform: "linux/s390x"},
+			wantErr: true,
+		},
+		{
+			name: "Missing Image",
+			archive: func(t *testing.T) string {
+				return writeOCILayout(t, map[ociPlatform][]byte{{OS: "linux", Architecture: "amd64"}: alpine})
+			},
+			options: ArchiveOptions{Image: "2.0.0"},
+			wantErr: true,
+		},
+		{
+			name: "No Archive",
+			archive: func(t *testing.T) string {
+				return "../../test/images"
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).FromArchive(tt.archive(t), tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.FromArchive() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package tupliplib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// BakeFiles are the buildx bake files that are looked up in the working directory if no file is given.
+var BakeFiles = []string{"docker-bake.hcl", "docker-bake.json"}
+
+// BakeDefaultGroup is the bake group that contains the targets that are built by default.
+const BakeDefaultGroup = "default"
+
+// BakeOptions contain the parameters for reading the build definitions of a buildx bake file.
+type BakeOptions struct {
+	// Targets limits the considered targets to the given targets or groups.
+	Targets []string `name:"bake-target" placeholder:"NAME" help:"only read the given bake targets or groups (default: the default group or all targets)"`
+}
+
+// bakeSchema is the schema of the top-level blocks of a bake file.
+var bakeSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "group", LabelNames: []string{"name"}},
+		{Type: "target", LabelNames: []string{"name"}},
+	},
+}
+
+// bakeVariableSchema is the schema of the variable blocks of a bake file.
+var bakeVariableSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "default"}},
+}
+
+// bakeTarget is the part of a bake target that defines the build.
+type bakeTarget struct {
+	context    *string
+	dockerfile *string
+	target     *string
+	args       map[string]string
+	tags       []string
+	inherits   []string
+}
+
+// FromBake builds a tuplip source for each target of the given buildx bake file in HCL or JSON format.
+// The targets are the given targets and the targets of the given groups, the targets of the default group, or all
+// targets in the order of their names. Inherited targets are resolved. Variables take their values from the
+// environment or their defaults. The Dockerfiles are resolved from the context and dockerfile attributes relative
+// to the bake file and read with the args and target of the bake target and the given file options. If the
+// Dockerfile has no repository ARG, the repository of the first bake tag is used.
+func (t *Tuplip) FromBake(src string, options BakeOptions, fileOptions FileOptions) (
+	sources []*TuplipSource, err error) {
+
+	if src, err = findFile(src, BakeFiles, "bake"); err != nil {
+		return nil, err
+	}
+	logger.InfoWith("queueing read from bake file").
+		String("file", src).
+		Write()
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(src, ".json") {
+		file, diags = parser.ParseJSONFile(absSrc)
+	} else {
+		file, diags = parser.ParseHCLFile(absSrc)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	content, _, diags := file.Body.PartialContent(bakeSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	ctx := &hcl.EvalContext{Variables: make(map[string]cty.Value)}
+	groups := make(map[string][]string)
+	targets := make(map[string]hcl.Attributes)
+	for _, block := range content.Blocks {
+		name := block.Labels[0]
+		switch block.Type {
+		case "variable":
+			if ctx.Variables[name], err = bakeVariable(block, ctx); err != nil {
+				return nil, err
+			}
+		case "group":
+			attributes, diags := block.Body.JustAttributes()
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			if attribute, ok := attributes["targets"]; ok {
+				if groups[name], err = bakeStrings(attribute, ctx); err != nil {
+					return nil, err
+				}
+			}
+		case "target":
+			attributes, diags := block.Body.JustAttributes()
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			targets[name] = attributes
+		}
+	}
+	names := options.Targets
+	if len(names) == 0 {
+		if _, ok := groups[BakeDefaultGroup]; ok {
+			names = []string{BakeDefaultGroup}
+		} else {
+			for name := range targets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+	}
+	names, err = expandBakeGroups(names, groups, targets, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		target, err := resolveBakeTarget(name, targets, ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		build := buildDefinition{name: name, args: target.args}
+		var context, dockerfile string
+		if target.context != nil {
+			context = *target.context
+		}
+		if target.dockerfile != nil {
+			dockerfile = *target.dockerfile
+		}
+		build.dockerfile = resolveDockerfile(filepath.Dir(absSrc), context, dockerfile)
+		if target.target != nil {
+			build.target = *target.target
+		}
+		if len(target.tags) > 0 {
+			build.image = target.tags[0]
+		}
+		source, err := t.fromBuild(build, fileOptions)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("the bake file '%s' has no targets", src)
+	}
+	return sources, nil
+}
+
+// bakeVariable evaluates the value of the given variable block. The environment variable with the same name takes
+// precedence over the default.
+func bakeVariable(block *hcl.Block, ctx *hcl.EvalContext) (cty.Value, error) {
+	if value, ok := os.LookupEnv(block.Labels[0]); ok {
+		return cty.StringVal(value), nil
+	}
+	content, _, diags := block.Body.PartialContent(bakeVariableSchema)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	attribute, ok := content.Attributes["default"]
+	if !ok {
+		return cty.StringVal(""), nil
+	}
+	value, diags := attribute.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	return value, nil
+}
+
+// expandBakeGroups replaces the groups in the given names by their targets recursively.
+// Duplicate targets are omitted.
+func expandBakeGroups(names []string, groups map[string][]string, targets map[string]hcl.Attributes,
+	seen map[string]bool) (result []string, err error) {
+
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if members, ok := groups[name]; ok {
+			expanded, err := expandBakeGroups(members, groups, targets, seen)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, expanded...)
+		} else if _, ok := targets[name]; ok {
+			result = append(result, name)
+		} else {
+			return nil, fmt.Errorf("the bake target '%s' could not be found", name)
+		}
+	}
+	return result, nil
+}
+
+// resolveBakeTarget evaluates the attributes of the given target and the targets it inherits from.
+// The attributes of the target take precedence over the inherited ones, and later parents override earlier ones.
+func resolveBakeTarget(name string, targets map[string]hcl.Attributes, ctx *hcl.EvalContext,
+	path []string) (target bakeTarget, err error) {
+
+	for _, parent := range path {
+		if parent == name {
+			return target, fmt.Errorf("the bake target '%s' inherits from itself", name)
+		}
+	}
+	attributes, ok := targets[name]
+	if !ok {
+		return target, fmt.Errorf("the bake target '%s' could not be found", name)
+	}
+	target.args = make(map[string]string)
+	if attribute, ok := attributes["inherits"]; ok {
+		if target.inherits, err = bakeStrings(attribute, ctx); err != nil {
+			return target, err
+		}
+	}
+	for _, parentName := range target.inherits {
+		parent, err := resolveBakeTarget(parentName, targets, ctx, append(path, name))
+		if err != nil {
+			return target, err
+		}
+		target.context = bakeOverride(parent.context, target.context)
+		target.dockerfile = bakeOverride(parent.dockerfile, target.dockerfile)
+		target.target = bakeOverride(parent.target, target.target)
+		for key, value := range parent.args {
+			target.args[key] = value
+		}
+		if parent.tags != nil {
+			target.tags = parent.tags
+		}
+	}
+	for key, field := range map[string]**string{
+		"context": &target.context, "dockerfile": &target.dockerfile, "target": &target.target,
+	} {
+		if attribute, ok := attributes[key]; ok {
+			value, err := bakeString(attribute, ctx)
+			if err != nil {
+				return target, err
+			}
+			*field = &value
+		}
+	}
+	if attribute, ok := attributes["args"]; ok {
+		value, diags := attribute.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return target, diags
+		}
+		if !value.IsNull() {
+			for it := value.ElementIterator(); it.Next(); {
+				key, element := it.Element()
+				if element.IsNull() {
+					continue
+				}
+				if element, err = convert.Convert(element, cty.String); err != nil {
+					return target, fmt.Errorf("%s: the arg '%s' must be a string: %v", attribute.Range, key.AsString(),
+						err)
+				}
+				target.args[key.AsString()] = element.AsString()
+			}
+		}
+	}
+	if attribute, ok := attributes["tags"]; ok {
+		if target.tags, err = bakeStrings(attribute, ctx); err != nil {
+			logger.WarnWith("ignoring the tags of the bake target").
+				String("target", name).
+				String("error", err.Error()).
+				Write()
+		}
+	}
+	return target, nil
+}
+
+// bakeOverride returns the given value if it is set, and the given previous value otherwise.
+func bakeOverride(value *string, previous *string) *string {
+	if value != nil {
+		return value
+	}
+	return previous
+}
+
+// bakeString evaluates the given attribute as string.
+func bakeString(attribute *hcl.Attribute, ctx *hcl.EvalContext) (string, error) {
+	value, diags := attribute.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	if value.IsNull() {
+		return "", nil
+	}
+	value, err := convert.Convert(value, cty.String)
+	if err != nil {
+		return "", fmt.Errorf("%s: the attribute '%s' must be a string: %v", attribute.Range, attribute.Name, err)
+	}
+	return value.AsString(), nil
+}
+
+// bakeStrings evaluates the given attribute as list of strings.
+func bakeStrings(attribute *hcl.Attribute, ctx *hcl.EvalContext) (result []string, err error) {
+	value, diags := attribute.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if value.IsNull() {
+		return nil, nil
+	}
+	value, err = convert.Convert(value, cty.List(cty.String))
+	if err != nil {
+		return nil, fmt.Errorf("%s: the attribute '%s' must be a list of strings: %v", attribute.Range,
+			attribute.Name, err)
+	}
+	for it := value.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		if !element.IsNull() {
+			result = append(result, element.AsString())
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package tupliplib
+
+import (
+	"testing"
+)
+
+func TestTuplip_FromBake(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		options BakeOptions
+		want    []wantSource
+		wantErr bool
+	}{
+		{
+			name: "Default Group",
+			file: "../../test/builds/docker-bake.hcl",
+			want: []wantSource{
+				{"ghcr.io/gofunky/app", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.19"}},
+				{"gofunky/worker", []interface{}{"_:2.1", "python:3.12.1"}},
+			},
+		},
+		{
+			name:    "Variables From Environment",
+			file:    "../../test/builds/docker-bake.hcl",
+			env:     map[string]string{"ALPINE_VERSION": "3.20", "REGISTRY": "gofunky"},
+			options: BakeOptions{Targets: []string{"app"}},
+			want: []wantSource{
+				{"gofunky/app", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.20"}},
+			},
+		},
+		{
+			name:    "Inherited Target",
+			file:    "../../test/builds/docker-bake.hcl",
+			options: BakeOptions{Targets: []string{"debug", "default"}},
+			want: []wantSource{
+				{"", []interface{}{"_:1.0.0", "busybox:1.36"}},
+				{"ghcr.io/gofunky/app", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.19"}},
+				{"gofunky/worker", []interface{}{"_:2.1", "python:3.12.1"}},
+			},
+		},
+		{
+			name:    "Multiple Inherited Targets",
+			file:    "../../test/builds/docker-bake.hcl",
+			options: BakeOptions{Targets: []string{"stable"}},
+			want: []wantSource{
+				{"", []interface{}{"_:1.2.0", "golang:1.22.3", "alpine:3.17"}},
+			},
+		},
+		{
+			name: "JSON Format",
+			file: "../../test/builds/docker-bake.json",
+			want: []wantSource{
+				{"gofunky/app", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.18"}},
+			},
+		},
+		{
+			name:    "Unknown Target",
+			file:    "../../test/builds/docker-bake.hcl",
+			options: BakeOptions{Targets: []string{"unknown"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid File",
+			file:    "../../test/builds/compose.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			got, err := new(Tuplip).FromBake(tt.file, tt.options, FileOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromBake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			checkSources(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package tupliplib
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+// CIChannels are the channel rules that are applied to the built branches if no rules are specified.
+var CIChannels = []string{"main=edge", "master=edge"}
+
+// CIProviders are the providers of the supported CI systems in the order of their detection.
+var CIProviders = []CIProvider{GitHubActionsProvider{}, GitLabCIProvider{}, DockerHubProvider{}}
+
+// CIRef is the git reference that a CI system builds. At most one of its fields is set.
+type CIRef struct {
+	// Tag is the name of the built git tag.
+	Tag string
+	// Branch is the name of the built branch.
+	Branch string
+	// PullRequest is the number of the built pull request or merge request.
+	PullRequest string
+}
+
+// CIProvider detects a CI system from environment variables and determines the git reference that it builds.
+type CIProvider interface {
+	// Platform returns the CI system of the provider.
+	Platform() CIPlatform
+	// Detect checks if the given environment variables belong to the CI system.
+	Detect(env map[string]string) bool
+	// Ref returns the git reference that the CI system builds according to the given environment variables.
+	Ref(env map[string]string) CIRef
+}
+
+// GitHubActionsProvider is the CIProvider of GitHub Actions. It reads the ref from `GITHUB_REF`.
+type GitHubActionsProvider struct{}
+
+// Platform implements CIProvider.Platform.
+func (GitHubActionsProvider) Platform() CIPlatform {
+	return GitHubActions
+}
+
+// Detect implements CIProvider.Detect by checking `GITHUB_ACTIONS`.
+func (GitHubActionsProvider) Detect(env map[string]string) bool {
+	return env["GITHUB_ACTIONS"] == "true"
+}
+
+// Ref implements CIProvider.Ref. Pull requests have refs in the format `refs/pull/<number>/merge`.
+func (GitHubActionsProvider) Ref(env map[string]string) (ref CIRef) {
+	name := env["GITHUB_REF"]
+	switch {
+	case strings.HasPrefix(name, "refs/tags/"):
+		ref.Tag = strings.TrimPrefix(name, "refs/tags/")
+	case strings.HasPrefix(name, "refs/heads/"):
+		ref.Branch = strings.TrimPrefix(name, "refs/heads/")
+	case strings.HasPrefix(name, "refs/pull/"):
+		ref.PullRequest, _, _ = strings.Cut(strings.TrimPrefix(name, "refs/pull/"), RepositorySeparator)
+	}
+	return ref
+}
+
+// GitLabCIProvider is the CIProvider of GitLab CI. It reads the ref from `CI_COMMIT_TAG`, `CI_MERGE_REQUEST_IID`, and
+// `CI_COMMIT_BRANCH`.
+type GitLabCIProvider struct{}
+
+// Platform implements CIProvider.Platform.
+func (GitLabCIProvider) Platform() CIPlatform {
+	return GitLabCI
+}
+
+// Detect implements CIProvider.Detect by checking `GITLAB_CI`.
+func (GitLabCIProvider) Detect(env map[string]string) bool {
+	return env["GITLAB_CI"] == "true"
+}
+
+// Ref implements CIProvider.Ref.
+func (GitLabCIProvider) Ref(env map[string]string) (ref CIRef) {
+	switch {
+	case env["CI_COMMIT_TAG"] != "":
+		ref.Tag = env["CI_COMMIT_TAG"]
+	case env["CI_MERGE_REQUEST_IID"] != "":
+		ref.PullRequest = env["CI_MERGE_REQUEST_IID"]
+	default:
+		ref.Branch = env["CI_COMMIT_BRANCH"]
+	}
+	return ref
+}
+
+// DockerHubProvider is the CIProvider of the automated builds of Docker Hub. It reads the ref from `SOURCE_BRANCH`,
+// which contains either a branch or a tag name.
+type DockerHubProvider struct{}
+
+// Platform implements CIProvider.Platform.
+func (DockerHubProvider) Platform() CIPlatform {
+	return DockerHubAutobuild
+}
+
+// Detect implements CIProvider.Detect by checking `SOURCE_BRANCH` and `DOCKER_TAG`.
+func (DockerHubProvider) Detect(env map[string]string) bool {
+	return env["SOURCE_BRANCH"] != "" && env["DOCKER_TAG"] != ""
+}
+
+// Ref implements CIProvider.Ref. Since Docker Hub does not distinguish branches from tags, the source is a tag if the
+// last path element of its name is a semantic version with an optional `v` prefix, including pre-releases.
+func (DockerHubProvider) Ref(env map[string]string) (ref CIRef) {
+	name := env["SOURCE_BRANCH"]
+	if _, _, ok := tagVersion(path.Base(name)); ok {
+		ref.Tag = name
+	} else {
+		ref.Branch = name
+	}
+	return ref
+}
+
+// CIOptions contain the parameters for reading tag vectors from the environment of a CI system.
+type CIOptions struct {
+	// TagPrefix limits the release tags to the ones with the given prefix. The prefix is removed from the version.
+	TagPrefix string `name:"ci-tag-prefix" placeholder:"PREFIX" help:"only consider the release tags with the given prefix (e.g., 'app/')"`
+	// Channels map the built branches matching the glob patterns to channel vectors. An empty vector omits the channel
+	// vector. Unmatched branches yield an alias vector with their sanitized name. They default to the CIChannels.
+	Channels []string `name:"ci-channel" placeholder:"PATTERN=VECTOR" help:"map the branches matching the given glob pattern to the given channel vector, or omit the channel vector if it is empty (default: main=edge, master=edge)"`
+	// PullRequestChannel is the channel vector of pull requests. Its wildcard `*` is replaced by the number of the pull
+	// request. An empty vector omits the channel vector.
+	PullRequestChannel string `name:"ci-pr-channel" default:"pr-*" placeholder:"VECTOR" help:"the channel vector of pull requests whose '*' is replaced by their number, or empty to omit it"`
+}
+
+// detectCIProvider returns the first of the CIProviders that detects the given environment variables, or nil.
+func detectCIProvider(env map[string]string) CIProvider {
+	for _, provider := range CIProviders {
+		if provider.Detect(env) {
+			return provider
+		}
+	}
+	return nil
+}
+
+// tagVersion returns the version of the given tag name without its optional `v` prefix. ok is false if the version is
+// no semantic version.
+func tagVersion(name string) (version string, parsed semver.Version, ok bool) {
+	version = strings.TrimPrefix(name, "v")
+	parsed, err := semver.ParseTolerant(version)
+	return version, parsed, err == nil
+}
+
+// validChannel checks if the given channel vector is empty or consists of valid aliases that are separated by dashes
+// (e.g., `pr-123`).
+func validChannel(vector string) bool {
+	if vector == "" {
+		return true
+	}
+	for _, part := range strings.Split(vector, DockerTagSeparator) {
+		if part == "" || normalizeAlias(part) != part {
+			return false
+		}
+	}
+	return true
+}
+
+// FromCI builds a tuplip source from the given environment variables in the format `KEY=VALUE` (e.g., os.Environ) of
+// the CI system that is detected by the CIProviders. A built release tag yields the root tag vector version.
+// A built branch yields the channel vector of the first matching channel rule, and a built pull request yields the
+// channel vector of pull requests. The source is empty if the reference does not yield a tag vector, such as tags that
+// are no semantic versions. Pre-release and build metadata tags (e.g., `v1.2.3-rc.1`) are skipped with a warning, so
+// that they cannot overwrite the tags of the stable releases.
+func (t *Tuplip) FromCI(environ []string, options CIOptions) (source *TuplipSource, err error) {
+	env := environMap(environ)
+	provider := detectCIProvider(env)
+	if provider == nil {
+		return nil, fmt.Errorf("no supported CI system could be detected from the environment variables")
+	}
+	ref := provider.Ref(env)
+	logger.InfoWith("queueing read from CI environment").
+		String("platform", string(provider.Platform())).
+		String("tag", ref.Tag).
+		String("branch", ref.Branch).
+		String("pull request", ref.PullRequest).
+		Write()
+	var vector string
+	switch {
+	case ref.Tag != "":
+		if !strings.HasPrefix(ref.Tag, options.TagPrefix) {
+			break
+		}
+		version, parsed, ok := tagVersion(strings.TrimPrefix(ref.Tag, options.TagPrefix))
+		if ok && (len(parsed.Pre) > 0 || len(parsed.Build) > 0) {
+			logger.WarnWith("skipping pre-release or build metadata tag").
+				String("tag", ref.Tag).
+				Write()
+		} else if ok {
+			vector = WildcardDependency + VersionSeparator + version
+		}
+	case ref.PullRequest != "":
+		vector = strings.ReplaceAll(options.PullRequestChannel, "*", ref.PullRequest)
+		if !validChannel(vector) {
+			return nil, fmt.Errorf("the pull request channel '%s' is invalid", options.PullRequestChannel)
+		}
+	case ref.Branch != "":
+		if vector, err = ciChannel(ref.Branch, options.Channels); err != nil {
+			return nil, err
+		}
+	}
+	var vectors []string
+	if vector != "" {
+		vectors = append(vectors, vector)
+	} else {
+		logger.WarnWith("no tag vectors could be found in the CI environment").
+			String("platform", string(provider.Platform())).
+			Write()
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm}, nil
+}
+
+// ciChannel returns the channel vector of the given branch according to the given channel rules in the format
+// `PATTERN=VECTOR`. The rules default to the CIChannels.
+func ciChannel(branch string, rules []string) (string, error) {
+	if len(rules) == 0 {
+		rules = CIChannels
+	}
+	for _, rule := range rules {
+		pattern, vector, ok := strings.Cut(rule, ArgEquation)
+		if !ok || pattern == "" {
+			return "", fmt.Errorf("the channel rule '%s' must have the format 'PATTERN=VECTOR'", rule)
+		}
+		if !validChannel(vector) {
+			return "", fmt.Errorf("the channel rule '%s' has an invalid vector '%s'", rule, vector)
+		}
+		if matched, err := path.Match(pattern, branch); err != nil {
+			return "", err
+		} else if matched {
+			return vector, nil
+		}
+	}
+	return normalizeAlias(branch), nil
+}
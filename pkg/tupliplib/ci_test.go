@@ -0,0 +1,236 @@
+package tupliplib
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofunky/automi/collectors"
+)
+
+func TestCIProviders(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  []string
+		platform CIPlatform
+		want     CIRef
+	}{
+		{
+			name:     "GitHub Tag",
+			environ:  []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/tags/v1.2.3"},
+			platform: GitHubActions,
+			want:     CIRef{Tag: "v1.2.3"},
+		},
+		{
+			name:     "GitHub Branch",
+			environ:  []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/heads/feature/login"},
+			platform: GitHubActions,
+			want:     CIRef{Branch: "feature/login"},
+		},
+		{
+			name:     "GitHub Pull Request",
+			environ:  []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/pull/123/merge", "GITHUB_HEAD_REF=fix"},
+			platform: GitHubActions,
+			want:     CIRef{PullRequest: "123"},
+		},
+		{
+			name:     "GitLab Tag",
+			environ:  []string{"GITLAB_CI=true", "CI_COMMIT_TAG=1.2.3", "CI_COMMIT_REF_NAME=1.2.3"},
+			platform: GitLabCI,
+			want:     CIRef{Tag: "1.2.3"},
+		},
+		{
+			name:     "GitLab Branch",
+			environ:  []string{"GITLAB_CI=true", "CI_COMMIT_BRANCH=main", "CI_COMMIT_REF_NAME=main"},
+			platform: GitLabCI,
+			want:     CIRef{Branch: "main"},
+		},
+		{
+			name:     "GitLab Merge Request",
+			environ:  []string{"GITLAB_CI=true", "CI_MERGE_REQUEST_IID=7", "CI_COMMIT_REF_NAME=fix"},
+			platform: GitLabCI,
+			want:     CIRef{PullRequest: "7"},
+		},
+		{
+			name:     "Docker Hub Tag",
+			environ:  []string{"SOURCE_BRANCH=v2.0", "DOCKER_TAG=2.0"},
+			platform: DockerHubAutobuild,
+			want:     CIRef{Tag: "v2.0"},
+		},
+		{
+			name:     "Docker Hub Prefixed Tag",
+			environ:  []string{"SOURCE_BRANCH=app/2.0.1", "DOCKER_TAG=2.0.1"},
+			platform: DockerHubAutobuild,
+			want:     CIRef{Tag: "app/2.0.1"},
+		},
+		{
+			name:     "Docker Hub Pre-Release Tag",
+			environ:  []string{"SOURCE_BRANCH=v2.0.0-rc.1", "DOCKER_TAG=2.0.0-rc.1"},
+			platform: DockerHubAutobuild,
+			want:     CIRef{Tag: "v2.0.0-rc.1"},
+		},
+		{
+			name:     "Docker Hub Branch",
+			environ:  []string{"SOURCE_BRANCH=master", "DOCKER_TAG=latest"},
+			platform: DockerHubAutobuild,
+			want:     CIRef{Branch: "master"},
+		},
+		{
+			name:     "Precedence",
+			environ:  []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/heads/main", "SOURCE_BRANCH=dev", "DOCKER_TAG=dev"},
+			platform: GitHubActions,
+			want:     CIRef{Branch: "main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := environMap(tt.environ)
+			provider := detectCIProvider(env)
+			if provider == nil {
+				t.Fatalf("detectCIProvider() = nil, want %v", tt.platform)
+			}
+			if got := provider.Platform(); got != tt.platform {
+				t.Errorf("Platform() = %v, want %v", got, tt.platform)
+			}
+			if got := DetectCIPlatform(tt.environ); got != tt.platform {
+				t.Errorf("DetectCIPlatform() = %v, want %v", got, tt.platform)
+			}
+			if got := provider.Ref(env); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ref() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTuplip_FromCI(t *testing.T) {
+	defaults := CIOptions{PullRequestChannel: "pr-*"}
+	tests := []struct {
+		name    string
+		environ []string
+		options CIOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Release Tag",
+			environ: []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/tags/v1.2.3"},
+			options: defaults,
+			want:    []string{"_:1.2.3"},
+		},
+		{
+			name:    "Prefixed Release Tag",
+			environ: []string{"GITLAB_CI=true", "CI_COMMIT_TAG=app/v2.0.0"},
+			options: CIOptions{TagPrefix: "app/"},
+			want:    []string{"_:2.0.0"},
+		},
+		{
+			name:    "Release Tag Without Prefix",
+			environ: []string{"GITLAB_CI=true", "CI_COMMIT_TAG=v2.0.0"},
+			options: CIOptions{TagPrefix: "app/"},
+		},
+		{
+			name:    "Pre-Release Tag",
+			environ: []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/tags/v1.2.3-rc.1"},
+			options: defaults,
+		},
+		{
+			name:    "Build Metadata Tag",
+			environ: []string{"GITLAB_CI=true", "CI_COMMIT_TAG=1.2.3+build.5"},
+			options: defaults,
+		},
+		{
+			name:    "Docker Hub Pre-Release Tag",
+			environ: []string{"SOURCE_BRANCH=v2.0.0-beta.1", "DOCKER_TAG=2.0.0-beta.1"},
+			options: defaults,
+		},
+		{
+			name:    "Unversioned Tag",
+			environ: []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/tags/nightly"},
+			options: defaults,
+		},
+		{
+			name:    "Default Branch",
+			environ: []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/heads/main"},
+			options: defaults,
+			want:    []string{"edge"},
+		},
+		{
+			name:    "Other Branch",
+			environ: []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/heads/feature/Login"},
+			options: defaults,
+			want:    []string{"feature_login"},
+		},
+		{
+			name:    "Channel Rules",
+			environ: []string{"SOURCE_BRANCH=release/1.x", "DOCKER_TAG=next"},
+			options: CIOptions{Channels: []string{"main=edge", "release/*=next"}},
+			want:    []string{"next"},
+		},
+		{
+			name:    "Omitted Channel",
+			environ: []string{"GITLAB_CI=true", "CI_COMMIT_BRANCH=feature/x"},
+			options: CIOptions{Channels: []string{"feature/*="}},
+		},
+		{
+			name:    "Pull Request",
+			environ: []string{"GITHUB_ACTIONS=true", "GITHUB_REF=refs/pull/123/merge"},
+			options: defaults,
+			want:    []string{"pr-123"},
+		},
+		{
+			name:    "Custom Pull Request Channel",
+			environ: []string{"GITLAB_CI=true", "CI_MERGE_REQUEST_IID=7"},
+			options: CIOptions{PullRequestChannel: "mr*-preview"},
+			want:    []string{"mr7-preview"},
+		},
+		{
+			name:    "Omitted Pull Request Channel",
+			environ: []string{"GITLAB_CI=true", "CI_MERGE_REQUEST_IID=7"},
+		},
+		{
+			name:    "Invalid Pull Request Channel",
+			environ: []string{"GITLAB_CI=true", "CI_MERGE_REQUEST_IID=7"},
+			options: CIOptions{PullRequestChannel: "pr/*"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Channel Rule",
+			environ: []string{"GITLAB_CI=true", "CI_COMMIT_BRANCH=main"},
+			options: CIOptions{Channels: []string{"main"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Channel Vector",
+			environ: []string{"GITLAB_CI=true", "CI_COMMIT_BRANCH=main"},
+			options: CIOptions{Channels: []string{"main=Edge"}},
+			wantErr: true,
+		},
+		{
+			name:    "No CI",
+			environ: []string{"CI=true", "SOURCE_BRANCH=main"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := new(Tuplip).FromCI(tt.environ, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromCI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			collector := collectors.Slice()
+			source.stream.Into(collector)
+			if err = <-source.stream.Open(); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, item := range collector.Get() {
+				got = append(got, item.(string))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromCI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
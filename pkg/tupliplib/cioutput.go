@@ -0,0 +1,167 @@
+package tupliplib
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// CIPlatform depicts a CI system.
+type CIPlatform string
+
+const (
+	// NoCI disables the CI outputs.
+	NoCI CIPlatform = "none"
+	// AutoCI detects the CI system from the environment variables.
+	AutoCI CIPlatform = "auto"
+	// GitHubActions writes the step outputs and the step summary of GitHub Actions.
+	GitHubActions CIPlatform = "github"
+	// GitLabCI writes a dotenv report of GitLab CI.
+	GitLabCI CIPlatform = "gitlab"
+	// DockerHubAutobuild is an automated build of Docker Hub, which has no outputs.
+	DockerHubAutobuild CIPlatform = "dockerhub"
+)
+
+const (
+	// GitHubOutputEnv is the environment variable that contains the path of the step output file of GitHub Actions.
+	GitHubOutputEnv = "GITHUB_OUTPUT"
+	// GitHubStepSummaryEnv is the environment variable that contains the path of the step summary file of GitHub
+	// Actions.
+	GitHubStepSummaryEnv = "GITHUB_STEP_SUMMARY"
+	// GitHubTagsOutput is the name of the step output that contains the Docker tags.
+	GitHubTagsOutput = "tags"
+	// GitLabTagsVariable is the variable of the dotenv report that contains the Docker tags.
+	GitLabTagsVariable = "TUPLIP_TAGS"
+	// GitLabDotenv is the default path of the dotenv report.
+	GitLabDotenv = "tuplip.env"
+	// ciTagSeparator separates the Docker tags in single-line CI outputs.
+	ciTagSeparator = ","
+)
+
+// CIOutputOptions contain the parameters for writing the Docker tags to the outputs of a CI system.
+type CIOutputOptions struct {
+	// Platform is the CI system that receives the outputs, or AutoCI to detect it from the environment variables.
+	Platform CIPlatform `name:"ci" enum:"none,auto,github,gitlab" default:"none" env:"TUPLIP_CI" help:"write the Docker tags to the outputs of the given CI system (none, auto, github, or gitlab)"`
+	// Dotenv is the path of the dotenv report for GitLab CI. It defaults to GitLabDotenv.
+	Dotenv string `name:"ci-dotenv" type:"path" default:"tuplip.env" placeholder:"FILE" help:"the path of the dotenv report for GitLab CI"`
+}
+
+// DetectCIPlatform determines the CI system from the given environment variables in the format `KEY=VALUE`
+// (e.g., os.Environ) using the CIProviders. It returns NoCI if no supported CI system is detected.
+func DetectCIPlatform(environ []string) CIPlatform {
+	if provider := detectCIProvider(environMap(environ)); provider != nil {
+		return provider.Platform()
+	}
+	return NoCI
+}
+
+// environMap converts the given environment variables in the format `KEY=VALUE` to a map.
+func environMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, variable := range environ {
+		key, value, _ := strings.Cut(variable, ArgEquation)
+		env[key] = value
+	}
+	return env
+}
+
+// WriteCIOutputs writes the given Docker tags to the outputs of the CI system of the given options.
+// The tags are ordered like the ones of WriteTags. In GitHub Actions, the tags are appended to the file of
+// GitHubOutputEnv as multiline `tags` output and as table to the file of GitHubStepSummaryEnv, if it is set.
+// In GitLab CI, the dotenv report contains the comma-separated tags in the GitLabTagsVariable.
+func WriteCIOutputs(environ []string, tags []string, options CIOutputOptions) (err error) {
+	platform := options.Platform
+	if platform == AutoCI {
+		platform = DetectCIPlatform(environ)
+		logger.InfoWith("detected CI platform").
+			String("platform", string(platform)).
+			Write()
+	}
+	tags = sortTags(tags)
+	env := environMap(environ)
+	switch platform {
+	case NoCI, DockerHubAutobuild, "":
+		return nil
+	case GitHubActions:
+		output := env[GitHubOutputEnv]
+		if output == "" {
+			return fmt.Errorf("the GitHub Actions outputs require the environment variable %s", GitHubOutputEnv)
+		}
+		if err = appendFile(output, func(writer io.Writer) error {
+			return writeGitHubOutput(writer, GitHubTagsOutput, tags)
+		}); err != nil {
+			return err
+		}
+		if summary := env[GitHubStepSummaryEnv]; summary != "" {
+			return appendFile(summary, func(writer io.Writer) error {
+				return writeGitHubSummary(writer, tags)
+			})
+		}
+		return nil
+	case GitLabCI:
+		dotenv := options.Dotenv
+		if dotenv == "" {
+			dotenv = GitLabDotenv
+		}
+		logger.InfoWith("writing CI output").
+			String("file", dotenv).
+			Write()
+		content := fmt.Sprintf("%s%s%s\n", GitLabTagsVariable, ArgEquation, strings.Join(tags, ciTagSeparator))
+		return os.WriteFile(dotenv, []byte(content), 0644)
+	default:
+		return fmt.Errorf("the CI platform '%s' is not supported", platform)
+	}
+}
+
+// appendFile appends the content of the given write function to the given file.
+func appendFile(path string, write func(writer io.Writer) error) (err error) {
+	logger.InfoWith("writing CI output").
+		String("file", path).
+		Write()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err = write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeGitHubOutput writes the given values as multiline step output with the given name. The values are enclosed
+// by a random delimiter so that they cannot end the output prematurely.
+func writeGitHubOutput(writer io.Writer, name string, values []string) error {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return err
+	}
+	delimiter := "ghadelimiter_" + hex.EncodeToString(random)
+	var builder strings.Builder
+	builder.WriteString(name + "<<" + delimiter + "\n")
+	for _, value := range values {
+		builder.WriteString(value + "\n")
+	}
+	builder.WriteString(delimiter + "\n")
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+// writeGitHubSummary writes the given Docker tags as Markdown table for the step summary.
+func writeGitHubSummary(writer io.Writer, tags []string) error {
+	var builder strings.Builder
+	builder.WriteString("### Docker tags\n\n")
+	builder.WriteString("| Repository | Tag | Vectors |\n")
+	builder.WriteString("|------------|-----|---------|\n")
+	for _, tag := range tags {
+		record := NewTagRecord(tag)
+		builder.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", record.Repository, record.Tag,
+			strings.Join(record.Vectors, Space)))
+	}
+	builder.WriteString("\n")
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
@@ -0,0 +1,138 @@
+package tupliplib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectCIPlatform(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		want    CIPlatform
+	}{
+		{"GitHub Actions", []string{"CI=true", "GITHUB_ACTIONS=true"}, GitHubActions},
+		{"GitLab CI", []string{"CI=true", "GITLAB_CI=true"}, GitLabCI},
+		{"Unknown CI", []string{"CI=true"}, NoCI},
+		{"Disabled GitHub Actions", []string{"GITHUB_ACTIONS=false"}, NoCI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectCIPlatform(tt.environ); got != tt.want {
+				t.Errorf("DetectCIPlatform() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCIOutputs_GitHub(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output")
+	summary := filepath.Join(dir, "summary")
+	if err := os.WriteFile(output, []byte("digest=sha256:abc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	environ := []string{"GITHUB_ACTIONS=true", GitHubOutputEnv + "=" + output, GitHubStepSummaryEnv + "=" + summary}
+	tags := []string{"gofunky/app:1", "gofunky/app:1.2-alpine", "gofunky/app:1"}
+	if err := WriteCIOutputs(environ, tags, CIOutputOptions{Platform: AutoCI}); err != nil {
+		t.Fatalf("WriteCIOutputs() error = %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("WriteCIOutputs() output = %q, want 5 lines", content)
+	}
+	if lines[0] != "digest=sha256:abc" {
+		t.Errorf("WriteCIOutputs() replaced the existing output %q", lines[0])
+	}
+	delimiter := strings.TrimPrefix(lines[1], GitHubTagsOutput+"<<")
+	if delimiter == lines[1] || !strings.HasPrefix(delimiter, "ghadelimiter_") || lines[4] != delimiter {
+		t.Errorf("WriteCIOutputs() output = %q, want a multiline value with delimiter", content)
+	}
+	if lines[2] != "gofunky/app:1.2-alpine" || lines[3] != "gofunky/app:1" {
+		t.Errorf("WriteCIOutputs() tags = %q, want the sorted tags", lines[2:4])
+	}
+	content, err = os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "### Docker tags\n\n" +
+		"| Repository | Tag | Vectors |\n" +
+		"|------------|-----|---------|\n" +
+		"| gofunky/app | `1.2-alpine` | 1.2 alpine |\n" +
+		"| gofunky/app | `1` | 1 |\n\n"
+	if string(content) != want {
+		t.Errorf("WriteCIOutputs() summary = %q, want %q", content, want)
+	}
+}
+
+func TestWriteCIOutputs(t *testing.T) {
+	tags := []string{"alpine", "1.2-alpine"}
+	tests := []struct {
+		name    string
+		environ []string
+		options CIOutputOptions
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "GitLab Dotenv",
+			environ: []string{"GITLAB_CI=true"},
+			options: CIOutputOptions{Platform: AutoCI},
+			want:    "TUPLIP_TAGS=1.2-alpine,alpine\n",
+		},
+		{
+			name:    "Explicit GitLab",
+			options: CIOutputOptions{Platform: GitLabCI},
+			want:    "TUPLIP_TAGS=1.2-alpine,alpine\n",
+		},
+		{
+			name:    "Undetected CI",
+			environ: []string{"CI=true"},
+			options: CIOutputOptions{Platform: AutoCI},
+		},
+		{
+			name:    "Disabled CI",
+			environ: []string{"GITLAB_CI=true"},
+			options: CIOutputOptions{Platform: NoCI},
+		},
+		{
+			name:    "GitHub Without Output",
+			options: CIOutputOptions{Platform: GitHubActions},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown CI",
+			options: CIOutputOptions{Platform: "jenkins"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dotenv := filepath.Join(t.TempDir(), GitLabDotenv)
+			tt.options.Dotenv = dotenv
+			err := WriteCIOutputs(tt.environ, tags, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteCIOutputs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			content, err := os.ReadFile(dotenv)
+			if tt.want == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf("WriteCIOutputs() wrote the dotenv report %q", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("WriteCIOutputs() dotenv = %q, want %q", content, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package tupliplib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeFiles are the docker-compose files that are looked up in the working directory if no file is given.
+var ComposeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ComposeOptions contain the parameters for reading the build definitions of a docker-compose file.
+type ComposeOptions struct {
+	// Services limits the considered services to the given names.
+	Services []string `name:"service" placeholder:"NAME" help:"only read the build definitions of the given services"`
+}
+
+// buildDefinition is a Dockerfile build that is defined in a docker-compose file or a buildx bake file.
+type buildDefinition struct {
+	// name is the name of the service or target.
+	name string
+	// dockerfile is the path of the Dockerfile.
+	dockerfile string
+	// args are the build args of the build.
+	args map[string]string
+	// target is the target stage of the build.
+	target string
+	// image is the image reference that the build is tagged with.
+	image string
+}
+
+// fromBuild builds a tuplip source from the Dockerfile of the given build definition.
+// The build args of the given file options override the ones of the build definition. The target of the file
+// options is only used if the build definition has no target. If the Dockerfile has no repository ARG, the repository
+// of the build image is used.
+func (t *Tuplip) fromBuild(build buildDefinition, fileOptions FileOptions) (source *TuplipSource, err error) {
+	logger.InfoWith("queueing build definition").
+		String("name", build.name).
+		String("file", build.dockerfile).
+		Write()
+	args := make(map[string]string)
+	for key, value := range build.args {
+		args[key] = value
+	}
+	for key, value := range fileOptions.BuildArgs {
+		args[key] = value
+	}
+	fileOptions.BuildArgs = args
+	if build.target != "" {
+		fileOptions.Target = build.target
+	}
+	source, err = t.FromFile(build.dockerfile, fileOptions)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", build.name, err)
+	}
+	if source.Repository == "" && build.image != "" {
+		source.Repository = parseReference(build.image).repository()
+	}
+	return source, nil
+}
+
+// composeFile is the part of a docker-compose file that defines the builds.
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+// composeService is a service of a docker-compose file.
+type composeService struct {
+	Image string       `yaml:"image"`
+	Build composeBuild `yaml:"build"`
+}
+
+// composeBuild is the build section of a docker-compose service.
+// It is given either as context path or as mapping.
+type composeBuild struct {
+	Context          string      `yaml:"context"`
+	Dockerfile       string      `yaml:"dockerfile"`
+	DockerfileInline string      `yaml:"dockerfile_inline"`
+	Args             composeArgs `yaml:"args"`
+	Target           string      `yaml:"target"`
+	defined          bool
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler and accepts the short syntax that only contains the context path.
+func (b *composeBuild) UnmarshalYAML(node *yaml.Node) error {
+	b.defined = true
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&b.Context)
+	}
+	type plain composeBuild
+	return node.Decode((*plain)(b))
+}
+
+// composeArgs are the build args of a docker-compose service.
+// They are given either as mapping or as list of `KEY=VALUE` entries.
+type composeArgs map[string]string
+
+// UnmarshalYAML implements yaml.Unmarshaler. Args without value are taken from the environment like docker-compose
+// does it.
+func (a *composeArgs) UnmarshalYAML(node *yaml.Node) error {
+	*a = make(composeArgs)
+	if node.Kind == yaml.SequenceNode {
+		var entries []string
+		if err := node.Decode(&entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			key, value, ok := strings.Cut(entry, ArgEquation)
+			if !ok {
+				value, ok = os.LookupEnv(key)
+			}
+			if ok {
+				(*a)[key] = value
+			}
+		}
+		return nil
+	}
+	var entries map[string]*string
+	if err := node.Decode(&entries); err != nil {
+		return err
+	}
+	for key, value := range entries {
+		if value != nil {
+			(*a)[key] = *value
+		} else if env, ok := os.LookupEnv(key); ok {
+			(*a)[key] = env
+		}
+	}
+	return nil
+}
+
+// interpolateCompose replaces the variable references in the scalar values of the given compose node by the values of
+// the given environment like docker-compose does it. The references have the same formats as the ARG references in
+// Dockerfiles (e.g., `${VAR:-default}`), and `$$` escapes a literal `$`. Unset variables are replaced by an empty
+// string.
+func interpolateCompose(node *yaml.Node, env map[string]string) {
+	if node.Kind == yaml.ScalarNode && strings.Contains(node.Value, "$") {
+		parts := strings.Split(node.Value, "$$")
+		for i, part := range parts {
+			parts[i] = expandVariables(part, env, node.Line, false)
+		}
+		node.Value = strings.Join(parts, "$")
+	}
+	for _, child := range node.Content {
+		interpolateCompose(child, env)
+	}
+}
+
+// FromCompose builds a tuplip source for each service with a build section in the given docker-compose file.
+// Variable references in the values of the compose file (e.g., `${GO_VERSION:-1.22}`) are interpolated from the
+// environment.
+// The Dockerfiles are resolved from the context and dockerfile paths relative to the compose file and read with the
+// build args and target of the services and the given file options. If the Dockerfile has no repository ARG, the
+// repository of the service image is used. The services are processed in the order of their names.
+func (t *Tuplip) FromCompose(src string, options ComposeOptions, fileOptions FileOptions) (
+	sources []*TuplipSource, err error) {
+
+	if src, err = findFile(src, ComposeFiles, "compose"); err != nil {
+		return nil, err
+	}
+	logger.InfoWith("queueing read from compose file").
+		String("file", src).
+		Write()
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(absSrc)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("invalid compose file '%s': %v", src, err)
+	}
+	interpolateCompose(&document, environMap(os.Environ()))
+	var compose composeFile
+	if err = document.Decode(&compose); err != nil {
+		return nil, fmt.Errorf("invalid compose file '%s': %v", src, err)
+	}
+	names := options.Services
+	if len(names) == 0 {
+		for name, service := range compose.Services {
+			if service.Build.defined {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		service, ok := compose.Services[name]
+		if !ok {
+			return nil, fmt.Errorf("the service '%s' could not be found in the compose file", name)
+		}
+		if !service.Build.defined {
+			return nil, fmt.Errorf("the service '%s' has no build section", name)
+		}
+		if service.Build.DockerfileInline != "" {
+			return nil, fmt.Errorf("the service '%s' has an inline Dockerfile, which is not supported", name)
+		}
+		build := buildDefinition{
+			name:       name,
+			dockerfile: resolveDockerfile(filepath.Dir(absSrc), service.Build.Context, service.Build.Dockerfile),
+			args:       service.Build.Args,
+			target:     service.Build.Target,
+			image:      service.Image,
+		}
+		source, err := t.fromBuild(build, fileOptions)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("the compose file '%s' has no services with a build section", src)
+	}
+	return sources, nil
+}
+
+// resolveDockerfile resolves the path of the Dockerfile from the given build context and Dockerfile path.
+// The context is relative to the given base directory and defaults to it. The Dockerfile is relative to the context
+// and defaults to `Dockerfile`.
+func resolveDockerfile(base string, context string, dockerfile string) string {
+	if !filepath.IsAbs(context) {
+		context = filepath.Join(base, context)
+	}
+	if dockerfile == "" {
+		dockerfile = Dockerfile
+	}
+	if filepath.IsAbs(dockerfile) {
+		return dockerfile
+	}
+	return filepath.Join(context, dockerfile)
+}
+
+// findFile returns the given file or, if it is empty, the first existing file of the given names.
+// The kind describes the files in the error message.
+func findFile(src string, names []string, kind string) (string, error) {
+	if src != "" {
+		return src, nil
+	}
+	for _, name := range names {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("none of the %s files %v could be found", kind, names)
+}
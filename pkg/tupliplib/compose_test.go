@@ -0,0 +1,75 @@
+package tupliplib
+
+import (
+	"testing"
+)
+
+func TestTuplip_FromCompose(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		options     ComposeOptions
+		fileOptions FileOptions
+		env         map[string]string
+		want        []wantSource
+		wantErr     bool
+	}{
+		{
+			name: "All Services",
+			file: "../../test/builds/compose.yaml",
+			want: []wantSource{
+				{"ghcr.io/gofunky/app", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.19"}},
+				{"", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.18", "busybox:1.36"}},
+				{"gofunky/worker", []interface{}{"_:2.1", "python:3.12.1"}},
+			},
+		},
+		{
+			name:        "Selected Service With Build Args",
+			file:        "../../test/builds/compose.yaml",
+			options:     ComposeOptions{Services: []string{"worker"}},
+			fileOptions: FileOptions{BuildArgs: map[string]string{"VERSION": "3.0"}},
+			want: []wantSource{
+				{"gofunky/worker", []interface{}{"_:3.0", "python:3.12.1"}},
+			},
+		},
+		{
+			name: "Interpolated Variables",
+			file: "../../test/builds/compose.yaml",
+			env:  map[string]string{"ALPINE_VERSION": "3.20", "WORKER_VERSION": ""},
+			want: []wantSource{
+				{"ghcr.io/gofunky/app", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.20"}},
+				{"", []interface{}{"_:1.0.0", "golang:1.22.3", "alpine:3.18", "busybox:1.36"}},
+				{"gofunky/worker", []interface{}{"_:2.1", "python:3.12.1"}},
+			},
+		},
+		{
+			name:    "Service Without Build",
+			file:    "../../test/builds/compose.yaml",
+			options: ComposeOptions{Services: []string{"db"}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown Service",
+			file:    "../../test/builds/compose.yaml",
+			options: ComposeOptions{Services: []string{"unknown"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid File",
+			file:    "../../test/builds/docker-bake.hcl",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			got, err := new(Tuplip).FromCompose(tt.file, tt.options, tt.fileOptions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromCompose() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			checkSources(t, got, tt.want)
+		})
+	}
+}
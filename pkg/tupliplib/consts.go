@@ -23,6 +23,24 @@ const (
 	// DockerFromInstruction is the FROM instruction in Dockerfiles.
 	DockerFromInstruction = "FROM"
 
+	// DockerCopyInstruction is the COPY instruction in Dockerfiles.
+	DockerCopyInstruction = "COPY"
+
+	// DockerEnvInstruction is the ENV instruction in Dockerfiles.
+	DockerEnvInstruction = "ENV"
+
+	// DockerLabelInstruction is the LABEL instruction in Dockerfiles.
+	DockerLabelInstruction = "LABEL"
+
+	// DirectivePrefix is the prefix of Dockerfile comments that contain tuplip directives.
+	DirectivePrefix = "tuplip:"
+
+	// OCIVersionLabel is the OCI image label that contains the version of the packaged software.
+	OCIVersionLabel = "org.opencontainers.image.version"
+
+	// DockerFromFlag is the flag of COPY instructions that references a build stage.
+	DockerFromFlag = "from"
+
 	// DockerArgInstruction is the ARG instruction in Dockerfiles.
 	DockerArgInstruction = "ARG"
 
@@ -30,12 +48,18 @@ const (
 	VersionArg = "VERSION"
 
 	// VersionInstruction is the Dockerfile's ARG VERSION.
+	//
+	// Deprecated: Dockerfiles are parsed into instructions, and the version ARG is configured by
+	// Conventions.VersionArg.
 	VersionInstruction = DockerArgInstruction + Space + VersionArg
 
 	// RepositoryArg is the argument name for the root repository.
 	RepositoryArg = "REPOSITORY"
 
 	// RepositoryInstruction is the Dockerfile's ARG REPOSITORY.
+	//
+	// Deprecated: Dockerfiles are parsed into instructions, and the repository ARG is configured by
+	// Conventions.RepositoryArg.
 	RepositoryInstruction = DockerArgInstruction + Space + RepositoryArg
 
 	// DockerScratch is the empty Docker base image alias.
@@ -48,9 +72,13 @@ const (
 	IgnoredAliasPrefix = "i__"
 
 	// ScratchInstruction is a simple Docker FROM instruction using scratch only.
+	//
+	// Deprecated: Dockerfiles are parsed into instructions; compare the image with DockerScratch instead.
 	ScratchInstruction = DockerFromInstruction + Space + DockerScratch
 
 	// WildcardInstruction depicts a FROM instruction with a wildcard dependency.
+	//
+	// Deprecated: the root tag vector is built from WildcardDependency and VersionSeparator.
 	WildcardInstruction = DockerFromInstruction + Space + WildcardDependency + VersionSeparator
 
 	// VersionChars are the characters that are used in a semantic version.
@@ -60,14 +88,28 @@ const (
 	ArgEquation = "="
 
 	// DockerAs is the alias in FROM instructions in Dockerfiles.
+	//
+	// Deprecated: FROM instructions are parsed case-insensitively; use DockerAsKeyword instead.
 	DockerAs = Space + "as" + Space
 
+	// DockerAsKeyword is the case-insensitive keyword that precedes the stage name in FROM instructions.
+	DockerAsKeyword = "AS"
+
+	// DockerEscape is the default escape character in Dockerfiles.
+	DockerEscape = "\\"
+
 	// VectorSeparator is the default tag vector separator.
 	VectorSeparator = " "
 
 	// RepositorySeparator is the character that separates the organization from the name.
 	RepositorySeparator = "/"
 
+	// DigestSeparator is the character that separates the digest from the image name in image references.
+	DigestSeparator = "@"
+
+	// DockerLocalhost is the registry domain of a local registry without port.
+	DockerLocalhost = "localhost"
+
 	// Dockerfile is the default name of a Dockerfile.
 	Dockerfile = "Dockerfile"
 )
@@ -0,0 +1,68 @@
+package tupliplib
+
+import "strings"
+
+// Conventions contain the names that tuplip uses to interpret Dockerfiles.
+// Empty fields fall back to the default conventions.
+type Conventions struct {
+	// VersionArg is the name of the ARG that contains the root tag vector version. It defaults to `VERSION`.
+	VersionArg string `name:"version-arg" default:"VERSION" help:"the name of the ARG that contains the root tag vector version"`
+	// RepositoryArg is the name of the ARG that contains the repository. It defaults to `REPOSITORY`.
+	RepositoryArg string `name:"repository-arg" default:"REPOSITORY" help:"the name of the ARG that contains the repository"`
+	// IgnoredAliasPrefix is the stage name prefix of FROM instructions that are ignored. It defaults to `i__`.
+	IgnoredAliasPrefix string `name:"ignore-prefix" default:"i__" help:"the stage name prefix of FROM instructions that are ignored"`
+	// VectorArgPrefix marks all ARGs with the given name prefix as tag vectors.
+	// The alias is the lower-case name without prefix and the value is the version (e.g., `ARG TUPLIP_ALPINE=3.19`).
+	// ARGs without value become alias tag vectors.
+	VectorArgPrefix string `name:"vector-arg-prefix" placeholder:"PREFIX" help:"treat all ARGs with the given name prefix as tag vectors"`
+	// ExtractionRules extract the tag vectors from the tags of matching images before the default heuristic is used.
+	// They also apply to the remote tags that are parsed by TuplipSource.Find.
+	ExtractionRules []ExtractionRule `kong:"-"`
+	// ExtractionPresets enables the built-in extraction rules for popular official images.
+	ExtractionPresets bool `kong:"-"`
+}
+
+// versionArg returns the name of the ARG that contains the root tag vector version.
+func (c Conventions) versionArg() string {
+	if c.VersionArg == "" {
+		return VersionArg
+	}
+	return c.VersionArg
+}
+
+// repositoryArg returns the name of the ARG that contains the repository.
+func (c Conventions) repositoryArg() string {
+	if c.RepositoryArg == "" {
+		return RepositoryArg
+	}
+	return c.RepositoryArg
+}
+
+// ignoredAliasPrefix returns the stage name prefix of FROM instructions that are ignored.
+func (c Conventions) ignoredAliasPrefix() string {
+	if c.IgnoredAliasPrefix == "" {
+		return IgnoredAliasPrefix
+	}
+	return c.IgnoredAliasPrefix
+}
+
+// isIgnored marks the given alias as ignored if it has the ignored alias prefix.
+func (c Conventions) isIgnored(alias string) bool {
+	return strings.HasPrefix(alias, c.ignoredAliasPrefix())
+}
+
+// vectorArg converts the given ARG to a tag vector if its name has the vector ARG prefix.
+// ok is false if the ARG is not a vector ARG.
+func (c Conventions) vectorArg(pair keyValue) (vector string, ok bool) {
+	if c.VectorArgPrefix == "" || !strings.HasPrefix(pair.key, c.VectorArgPrefix) {
+		return "", false
+	}
+	alias := normalizeAlias(strings.TrimPrefix(pair.key, c.VectorArgPrefix))
+	if alias == "" || c.isIgnored(alias) {
+		return "", true
+	}
+	if value := strings.TrimSpace(pair.value); value != "" {
+		return alias + VersionSeparator + value, true
+	}
+	return alias, true
+}
@@ -0,0 +1,123 @@
+package tupliplib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// directive contains the settings of `# tuplip:` comments that apply to the following FROM instruction.
+type directive struct {
+	// alias overrides the alias that is derived from the image name.
+	alias string
+	// ignore ignores the FROM instruction.
+	ignore bool
+	// options are the tag generation options of the vector that is derived from the FROM instruction.
+	options VectorOptions
+}
+
+// fileDirective contains the settings of `# tuplip:` comments that apply to the whole Dockerfile.
+type fileDirective struct {
+	// addLatest adds an additional 'latest' tag to the result set.
+	addLatest bool
+	// exclusiveLatest makes the `latest` tag vector version an exclusive tag if given.
+	exclusiveLatest bool
+	// filter excludes all tags without the given set of tag vectors from the output set.
+	filter []string
+}
+
+// apply returns a copy of the given tuplip options that is extended by the file directive.
+func (d fileDirective) apply(t Tuplip) Tuplip {
+	t.AddLatest = t.AddLatest || d.addLatest
+	t.ExclusiveLatest = t.ExclusiveLatest || d.exclusiveLatest
+	if len(d.filter) > 0 {
+		t.Filter = append(append([]string{}, t.Filter...), d.filter...)
+	}
+	return t
+}
+
+// resolveDirectives removes the `# tuplip:` comments from the given instructions and applies their directives.
+// The directives `alias`, `exclude`, and `ignore` apply to the following FROM instruction.
+// The directives `add-latest`, `exclusive-latest`, and `filter` apply to the whole Dockerfile.
+// Unknown directives fail with the line number of the comment.
+func resolveDirectives(instructions []instruction) (result []instruction, file fileDirective, err error) {
+	var pending directive
+	var pendingLine int
+	for _, inst := range instructions {
+		if inst.command != DirectivePrefix {
+			if pendingLine > 0 {
+				if inst.command != DockerFromInstruction {
+					return nil, file, fmt.Errorf("line %d: the tuplip directive must precede a FROM instruction",
+						pendingLine)
+				}
+				inst.directive = pending
+				pending, pendingLine = directive{}, 0
+			}
+			result = append(result, inst)
+			continue
+		}
+		for _, word := range inst.args {
+			key, value, hasValue := strings.Cut(word, ArgEquation)
+			switch key {
+			case "alias":
+				if value == "" || normalizeAlias(value) != value {
+					return nil, file, fmt.Errorf("line %d: invalid alias '%s' in tuplip directive", inst.line, value)
+				}
+				pending.alias = value
+				pendingLine = inst.line
+			case "exclude":
+				for _, part := range strings.Split(value, ",") {
+					switch strings.TrimSpace(part) {
+					case "major":
+						pending.options.ExcludeMajor = true
+					case "minor":
+						pending.options.ExcludeMinor = true
+					case "base":
+						pending.options.ExcludeBase = true
+					default:
+						return nil, file, fmt.Errorf("line %d: invalid exclude value '%s' in tuplip directive, "+
+							"expected major, minor, or base", inst.line, part)
+					}
+				}
+				pendingLine = inst.line
+			case "ignore":
+				if pending.ignore, err = directiveBool(value, hasValue, inst.line, key); err != nil {
+					return nil, file, err
+				}
+				pendingLine = inst.line
+			case "add-latest":
+				if file.addLatest, err = directiveBool(value, hasValue, inst.line, key); err != nil {
+					return nil, file, err
+				}
+			case "exclusive-latest":
+				if file.exclusiveLatest, err = directiveBool(value, hasValue, inst.line, key); err != nil {
+					return nil, file, err
+				}
+			case "filter":
+				for _, vector := range strings.Split(value, ",") {
+					if vector = strings.TrimSpace(vector); vector != "" {
+						file.filter = append(file.filter, vector)
+					}
+				}
+			default:
+				return nil, file, fmt.Errorf("line %d: unknown tuplip directive '%s'", inst.line, key)
+			}
+		}
+	}
+	if pendingLine > 0 {
+		return nil, file, fmt.Errorf("line %d: the tuplip directive must precede a FROM instruction", pendingLine)
+	}
+	return result, file, nil
+}
+
+// directiveBool parses the value of a boolean tuplip directive. A directive without value is true.
+func directiveBool(value string, hasValue bool, line int, key string) (bool, error) {
+	if !hasValue {
+		return true, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("line %d: invalid value '%s' for tuplip directive '%s'", line, value, key)
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package tupliplib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_resolveDirectives(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantDirectives []directive
+		wantFile       fileDirective
+		wantErr        string
+	}{
+		{
+			name:           "No Directives",
+			content:        "# comment\nFROM golang:1.22",
+			wantDirectives: []directive{{}},
+		},
+		{
+			name:           "Instruction Directive",
+			content:        "# tuplip: alias=go exclude=major\nFROM golang:1.22\nFROM alpine:3.19",
+			wantDirectives: []directive{{alias: "go", options: VectorOptions{ExcludeMajor: true}}, {}},
+		},
+		{
+			name:           "Multiple Comments And Blank Lines",
+			content:        "#tuplip: exclude=minor,base\n\n# other comment\n# tuplip: ignore=true\nFROM golang:1.22",
+			wantDirectives: []directive{{ignore: true, options: VectorOptions{ExcludeMinor: true, ExcludeBase: true}}},
+		},
+		{
+			name:           "File Directives",
+			content:        "# tuplip: add-latest filter=\"alpine, go\"\nFROM golang:1.22\n# tuplip: exclusive-latest",
+			wantDirectives: []directive{{}},
+			wantFile:       fileDirective{addLatest: true, exclusiveLatest: true, filter: []string{"alpine", "go"}},
+		},
+		{
+			name:    "Unknown Directive",
+			content: "FROM golang:1.22\n\n# tuplip: alias=go latest",
+			wantErr: "line 3: unknown tuplip directive 'latest'",
+		},
+		{
+			name:    "Directive Before ARG",
+			content: "# tuplip: alias=go\nARG VERSION=1.0\nFROM golang:1.22",
+			wantErr: "line 1: the tuplip directive must precede a FROM instruction",
+		},
+		{
+			name:    "Trailing Directive",
+			content: "FROM golang:1.22\n# tuplip: ignore",
+			wantErr: "line 2: the tuplip directive must precede a FROM instruction",
+		},
+		{
+			name:    "Invalid Exclude Value",
+			content: "# tuplip: exclude=patch\nFROM golang:1.22",
+			wantErr: "line 1: invalid exclude value 'patch'",
+		},
+		{
+			name:    "Invalid Alias",
+			content: "# tuplip: alias=go-lang\nFROM golang:1.22",
+			wantErr: "line 1: invalid alias 'go-lang'",
+		},
+		{
+			name:    "Invalid Boolean",
+			content: "# tuplip: add-latest=maybe\nFROM golang:1.22",
+			wantErr: "line 1: invalid value 'maybe' for tuplip directive 'add-latest'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructions, err := parseDockerfile(tt.content)
+			if err != nil {
+				t.Fatalf("parseDockerfile() error = %v", err)
+			}
+			instructions, gotFile, err := resolveDirectives(instructions)
+			if (err != nil) != (tt.wantErr != "") || (err != nil && !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Fatalf("resolveDirectives() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var gotDirectives []directive
+			for _, inst := range instructions {
+				gotDirectives = append(gotDirectives, inst.directive)
+			}
+			if !cmp.Equal(gotDirectives, tt.wantDirectives, cmp.AllowUnexported(directive{})) {
+				t.Errorf("resolveDirectives() directives = %+v, want %+v", gotDirectives, tt.wantDirectives)
+			}
+			if !cmp.Equal(gotFile, tt.wantFile, cmp.AllowUnexported(fileDirective{})) {
+				t.Errorf("resolveDirectives() file = %+v, want %+v", gotFile, tt.wantFile)
+			}
+		})
+	}
+}
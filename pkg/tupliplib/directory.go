@@ -0,0 +1,233 @@
+package tupliplib
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DockerfilePatterns are the glob patterns that discover the Dockerfiles in a directory if no patterns are specified.
+var DockerfilePatterns = []string{"Dockerfile", "Dockerfile.*", "*/Dockerfile", "*/Dockerfile.*"}
+
+// IgnoreFile is the file in the scanned directory that lists the glob patterns of the ignored paths.
+const IgnoreFile = ".tuplipignore"
+
+// IgnoredPatterns are the patterns of the paths that are ignored in addition to the ones of the IgnoreFile, such as the
+// per-Dockerfile ignore files of BuildKit (e.g., `Dockerfile.dockerignore`). The IgnoreFile can include them again.
+var IgnoredPatterns = []string{"*.dockerignore"}
+
+// DirectoryOptions contain the parameters for discovering the Dockerfiles in a directory.
+type DirectoryOptions struct {
+	// Patterns are the glob patterns of the Dockerfile paths relative to the directory. A `**` path segment matches
+	// any number of directories. They default to the DockerfilePatterns.
+	Patterns []string `name:"pattern" placeholder:"GLOB" help:"the glob patterns of the Dockerfile paths relative to the directory (default: Dockerfile, Dockerfile.*, */Dockerfile, */Dockerfile.*)"`
+	// RepositoryMap maps the Dockerfile paths matching the glob pattern to a repository if the Dockerfile has no
+	// repository ARG. The placeholder `{name}` is replaced by the image name derived from the path.
+	RepositoryMap []string `name:"repository-map" placeholder:"GLOB=REPOSITORY" help:"map the Dockerfile paths matching the glob pattern to a repository if the Dockerfile has no repository ARG"`
+}
+
+// FromDirectory builds a tuplip source for each Dockerfile in the given directory.
+// The Dockerfiles are discovered by the glob patterns of the directory options and read with the given file options.
+// Paths matching the IgnoredPatterns or the patterns in the IgnoreFile of the directory are skipped.
+func (t *Tuplip) FromDirectory(src string, options DirectoryOptions, fileOptions FileOptions) (
+	sources []*TuplipSource, err error) {
+
+	if src == "" {
+		src = "."
+	}
+	logger.InfoWith("queueing read from directory").
+		String("directory", src).
+		Write()
+	root, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	patterns := options.Patterns
+	if len(patterns) == 0 {
+		patterns = DockerfilePatterns
+	}
+	mapping, err := parseRepositoryMap(options.RepositoryMap)
+	if err != nil {
+		return nil, err
+	}
+	ignored, err := readIgnoreFile(filepath.Join(root, IgnoreFile))
+	if err != nil {
+		return nil, err
+	}
+	ignored = append(append(ignorePatterns{}, IgnoredPatterns...), ignored...)
+	err = filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if entry.Name() == ".git" || ignored.matches(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ignored.matches(rel) || !matchesAny(patterns, rel) {
+			return nil
+		}
+		logger.InfoWith("queueing Dockerfile from directory").
+			String("file", rel).
+			Write()
+		source, err := t.FromFile(file, fileOptions)
+		if err != nil {
+			return fmt.Errorf("%s: %v (add the path to the %s file to skip it)", rel, err, IgnoreFile)
+		}
+		if source.Repository == "" {
+			source.Repository = mapping.repository(rel)
+		}
+		sources = append(sources, source)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no Dockerfiles matching %v could be found in '%s'", patterns, src)
+	}
+	return sources, nil
+}
+
+// repositoryMapping is an ordered mapping from glob patterns of Dockerfile paths to repositories.
+type repositoryMapping []repositoryEntry
+
+// repositoryEntry maps the Dockerfile paths matching the pattern to the repository.
+type repositoryEntry struct {
+	pattern    string
+	repository string
+}
+
+// parseRepositoryMap parses the given repository mappings in the format `GLOB=REPOSITORY`.
+func parseRepositoryMap(entries []string) (mapping repositoryMapping, err error) {
+	for _, entry := range entries {
+		pattern, repository, ok := strings.Cut(entry, ArgEquation)
+		if !ok || pattern == "" || repository == "" {
+			return nil, fmt.Errorf("the repository mapping '%s' must have the format 'GLOB=REPOSITORY'", entry)
+		}
+		if _, err = path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern in repository mapping '%s': %v", entry, err)
+		}
+		mapping = append(mapping, repositoryEntry{pattern: pattern, repository: repository})
+	}
+	return mapping, nil
+}
+
+// repository returns the repository of the first mapping that matches the given Dockerfile path.
+// It returns an empty string if no mapping matches.
+func (m repositoryMapping) repository(file string) string {
+	for _, entry := range m {
+		if matchPath(entry.pattern, file) {
+			return strings.ReplaceAll(entry.repository, "{name}", imageName(file))
+		}
+	}
+	return ""
+}
+
+// imageName derives the image name from the given Dockerfile path.
+// It is the suffix of `Dockerfile.<name>` files or the name of the directory that contains the Dockerfile.
+func imageName(file string) string {
+	base := path.Base(file)
+	if strings.HasPrefix(base, Dockerfile+VersionDot) {
+		return strings.TrimPrefix(base, Dockerfile+VersionDot)
+	}
+	if strings.HasSuffix(base, VersionDot+Dockerfile) {
+		return strings.TrimSuffix(base, VersionDot+Dockerfile)
+	}
+	return path.Base(path.Dir(file))
+}
+
+// ignorePatterns are the patterns of an ignore file. Patterns with the prefix `!` include the matching paths again.
+type ignorePatterns []string
+
+// readIgnoreFile reads the patterns of the given ignore file. A missing ignore file has no patterns.
+// Blank lines and lines starting with `#` are skipped.
+func readIgnoreFile(file string) (patterns ignorePatterns, err error) {
+	handle, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer handle.Close()
+	scanner := bufio.NewScanner(handle)
+	for line := 1; scanner.Scan(); line++ {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if _, err = path.Match(strings.Trim(strings.TrimPrefix(pattern, "!"), "/"), ""); err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid pattern '%s'", IgnoreFile, line, pattern)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, scanner.Err()
+}
+
+// matches marks the given relative path as ignored. The last matching pattern wins.
+// Patterns without a slash match the name of any path component. Other patterns match the path from the
+// directory root.
+func (p ignorePatterns) matches(file string) (ignored bool) {
+	for _, pattern := range p {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "!"), "/")
+		var matched bool
+		if strings.Contains(pattern, "/") {
+			matched = matchPath(strings.TrimPrefix(pattern, "/"), file)
+		} else {
+			matched, _ = path.Match(pattern, path.Base(file))
+		}
+		if matched {
+			ignored = !negated
+		}
+	}
+	return ignored
+}
+
+// matchesAny marks if the given path matches any of the given glob patterns.
+func matchesAny(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if matchPath(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath matches the given slash-separated path against the given glob pattern.
+// In contrast to path.Match, a `**` segment matches any number of path segments.
+func matchPath(pattern string, file string) bool {
+	return matchSegments(strings.Split(pattern, RepositorySeparator), strings.Split(file, RepositorySeparator))
+}
+
+// matchSegments matches the given path segments against the given pattern segments.
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
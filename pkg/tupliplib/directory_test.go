@@ -0,0 +1,131 @@
+package tupliplib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+func TestTuplip_FromDirectory(t *testing.T) {
+	type wantSource struct {
+		repository string
+		vectors    []interface{}
+	}
+	tools := wantSource{vectors: []interface{}{"golang:1.22.3"}}
+	git := wantSource{repository: "gofunky/git", vectors: []interface{}{"alpine:3.19", "_:2.4.1"}}
+	node := wantSource{vectors: []interface{}{"node:20.11.0", "_:1.0"}}
+	tests := []struct {
+		name    string
+		dir     string
+		options DirectoryOptions
+		want    []wantSource
+		wantErr bool
+	}{
+		{
+			name: "Default Patterns",
+			dir:  "../../test/images",
+			want: []wantSource{tools, git, node},
+		},
+		{
+			name: "Repository Mapping",
+			dir:  "../../test/images",
+			options: DirectoryOptions{RepositoryMap: []string{
+				"Dockerfile.*=gofunky/{name}",
+				"*/Dockerfile=gofunky/{name}-image",
+			}},
+			want: []wantSource{
+				{repository: "gofunky/tools", vectors: tools.vectors},
+				git,
+				{repository: "gofunky/node-image", vectors: node.vectors},
+			},
+		},
+		{
+			name:    "Recursive Pattern",
+			dir:     "../../test/images",
+			options: DirectoryOptions{Patterns: []string{"**/Dockerfile"}},
+			want:    []wantSource{git, {vectors: []interface{}{"python:3.12"}}, node},
+		},
+		{
+			name:    "No Matching Dockerfiles",
+			dir:     "../../test/images",
+			options: DirectoryOptions{Patterns: []string{"*.Dockerfile"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Repository Mapping",
+			dir:     "../../test/images",
+			options: DirectoryOptions{RepositoryMap: []string{"gofunky/node"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, err := new(Tuplip).FromDirectory(tt.dir, tt.options, FileOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromDirectory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(sources) != len(tt.want) {
+				t.Fatalf("Tuplip.FromDirectory() = %d sources, want %d", len(sources), len(tt.want))
+			}
+			for i, src := range sources {
+				if src.Repository != tt.want[i].repository {
+					t.Errorf("Tuplip.FromDirectory()[%d] repository = %v, want %v", i, src.Repository,
+						tt.want[i].repository)
+				}
+				if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.want[i].vectors...)) {
+					t.Errorf("Tuplip.FromDirectory()[%d] = %v, want %v", i, got, tt.want[i].vectors)
+				}
+			}
+		})
+	}
+}
+
+func TestTuplip_FromDirectory_Unparsable(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Dockerfile":              "FROM alpine:3.19\n",
+		"Dockerfile.dockerignore": "node_modules\n",
+		IgnoreFile:                "!Dockerfile.dockerignore\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := new(Tuplip).FromDirectory(dir, DirectoryOptions{}, FileOptions{})
+	if err == nil {
+		t.Fatal("Tuplip.FromDirectory() error = nil, want an error for the included ignore file")
+	}
+	for _, want := range []string{"Dockerfile.dockerignore", IgnoreFile} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Tuplip.FromDirectory() error = %v, want it to mention %s", err, want)
+		}
+	}
+}
+
+func Test_ignorePatterns_matches(t *testing.T) {
+	patterns := ignorePatterns{"legacy/", "*.bak", "/build/**/Dockerfile", "!build/keep/Dockerfile"}
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{file: "legacy", want: true},
+		{file: "images/legacy", want: true},
+		{file: "images/Dockerfile.bak", want: true},
+		{file: "images/Dockerfile", want: false},
+		{file: "build/Dockerfile", want: true},
+		{file: "build/a/b/Dockerfile", want: true},
+		{file: "build/keep/Dockerfile", want: false},
+		{file: "other/build/Dockerfile", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := patterns.matches(tt.file); got != tt.want {
+				t.Errorf("ignorePatterns.matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package tupliplib
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// parserDirective matches a Dockerfile parser directive such as `# escape=`.
+var parserDirective = regexp.MustCompile(`^#\s*([a-zA-Z][a-zA-Z0-9]*)\s*=\s*(.+?)\s*$`)
+
+// instruction is a parsed Dockerfile instruction.
+type instruction struct {
+	// command is the upper-case instruction keyword (e.g., `FROM`).
+	command string
+	// flags are the instruction flags (e.g., `--platform`) mapped by their names without dashes.
+	flags map[string]string
+	// args are the unquoted arguments of the instruction following the flags.
+	args []string
+	// line is the line number where the instruction starts.
+	line int
+	// stageRef marks FROM instructions that are based on an earlier build stage of the same Dockerfile.
+	stageRef bool
+	// directive contains the settings of the `# tuplip:` comments preceding a FROM instruction.
+	directive directive
+}
+
+// image returns the base image reference of a FROM instruction.
+func (i instruction) image() string {
+	if len(i.args) == 0 {
+		return ""
+	}
+	return i.args[0]
+}
+
+// stageName returns the build stage name of a FROM instruction (i.e., the name following `AS`).
+func (i instruction) stageName() string {
+	if len(i.args) > 2 && strings.EqualFold(i.args[1], DockerAsKeyword) {
+		return i.args[2]
+	}
+	return ""
+}
+
+// argValue returns the default value of the given ARG name if the instruction is an ARG instruction that defines it.
+// declared is true if the ARG is declared, even without default value.
+func (i instruction) argValue(name string) (value string, declared bool) {
+	if i.command != DockerArgInstruction {
+		return "", false
+	}
+	for _, arg := range i.args {
+		argName, argValue, _ := strings.Cut(arg, ArgEquation)
+		if argName == name {
+			return argValue, true
+		}
+	}
+	return "", false
+}
+
+// keyValue is a key-value pair of an ARG, ENV, or LABEL instruction.
+type keyValue struct {
+	key   string
+	value string
+}
+
+// pairs returns the key-value pairs of an ARG, ENV, or LABEL instruction.
+// An ENV instruction in the legacy format `ENV KEY value` is returned as a single pair.
+func (i instruction) pairs() (pairs []keyValue) {
+	switch i.command {
+	case DockerEnvInstruction:
+		if len(i.args) > 1 && !strings.Contains(i.args[0], ArgEquation) {
+			return []keyValue{{key: i.args[0], value: strings.Join(i.args[1:], Space)}}
+		}
+	case DockerArgInstruction, DockerLabelInstruction:
+	default:
+		return nil
+	}
+	for _, arg := range i.args {
+		key, value, _ := strings.Cut(arg, ArgEquation)
+		pairs = append(pairs, keyValue{key: key, value: value})
+	}
+	return pairs
+}
+
+// parseDockerfile parses the instructions of the given Dockerfile content.
+// It handles case-insensitive keywords, flags, comments, line continuations, and the escape parser directive.
+// Comments with tuplip directives are returned as instructions with the directive prefix as command.
+func parseDockerfile(content string) (instructions []instruction, err error) {
+	escape := DockerEscape
+	directives := true
+	var logical strings.Builder
+	var start int
+	finish := func() error {
+		if start == 0 {
+			return nil
+		}
+		inst, err := parseInstruction(logical.String(), start, escape)
+		if err != nil {
+			return err
+		}
+		instructions = append(instructions, inst)
+		logical.Reset()
+		start = 0
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for n, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, "#"))
+			if start == 0 && strings.HasPrefix(text, DirectivePrefix) {
+				words, err := splitWords(strings.TrimPrefix(text, DirectivePrefix), escape)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %v", n+1, err)
+				}
+				instructions = append(instructions, instruction{command: DirectivePrefix, flags: make(map[string]string),
+					args: words, line: n + 1})
+				directives = false
+				continue
+			}
+			if directives {
+				if match := parserDirective.FindStringSubmatch(trimmed); match != nil {
+					if strings.EqualFold(match[1], "escape") {
+						if match[2] != "\\" && match[2] != "`" {
+							return nil, fmt.Errorf("line %d: invalid escape character '%s'", n+1, match[2])
+						}
+						escape = match[2]
+					}
+					continue
+				}
+			}
+			directives = false
+			continue
+		}
+		directives = false
+		if trimmed == "" {
+			continue
+		}
+		if start == 0 {
+			start = n + 1
+			line = strings.TrimLeftFunc(line, unicode.IsSpace)
+		}
+		withoutTrailing := strings.TrimRightFunc(line, unicode.IsSpace)
+		if strings.HasSuffix(withoutTrailing, escape) {
+			logical.WriteString(strings.TrimSuffix(withoutTrailing, escape))
+			continue
+		}
+		logical.WriteString(line)
+		if err = finish(); err != nil {
+			return nil, err
+		}
+	}
+	if err = finish(); err != nil {
+		return nil, err
+	}
+	return instructions, nil
+}
+
+// buildStage is a build stage of a Dockerfile.
+type buildStage struct {
+	// from is the index of the FROM instruction that starts the stage.
+	from int
+	// name is the name of the stage or its index if it has no name.
+	name string
+	// dependencies are the indices of the stages that the stage is based on or copies from.
+	dependencies []int
+}
+
+// linkStages determines the build stages of the given instructions and their dependencies.
+// FROM instructions that are based on an earlier build stage are marked as stage references.
+func linkStages(instructions []instruction) (stages []buildStage) {
+	names := make(map[string]int)
+	for i, inst := range instructions {
+		switch inst.command {
+		case DockerFromInstruction:
+			stage := buildStage{from: i, name: strconv.Itoa(len(stages))}
+			if base, ok := names[strings.ToLower(inst.image())]; ok {
+				instructions[i].stageRef = true
+				stage.dependencies = append(stage.dependencies, base)
+			}
+			if name := inst.stageName(); name != "" {
+				stage.name = name
+				names[strings.ToLower(name)] = len(stages)
+			}
+			stages = append(stages, stage)
+		case DockerCopyInstruction:
+			from, ok := inst.flags[DockerFromFlag]
+			if !ok || len(stages) == 0 {
+				continue
+			}
+			current := &stages[len(stages)-1]
+			if source, ok := names[strings.ToLower(from)]; ok {
+				current.dependencies = append(current.dependencies, source)
+			} else if source, err := strconv.Atoi(from); err == nil && source >= 0 && source < len(stages)-1 {
+				current.dependencies = append(current.dependencies, source)
+			}
+		}
+	}
+	return stages
+}
+
+// selectStages removes the instructions of all stages that the given target stage does not depend on
+// and of all stages whose names do not match the include patterns or match the exclude patterns.
+// The instructions before the first FROM instruction are always kept. The declarations of the given ARGs, such as the
+// convention ARGs that describe the whole image, are kept in the removed stages. An empty target selects all stages.
+func selectStages(instructions []instruction, stages []buildStage, target string, include []string,
+	exclude []string, keptArgs []string) (result []instruction, err error) {
+
+	selected := make([]bool, len(stages))
+	if target == "" {
+		for i := range selected {
+			selected[i] = true
+		}
+	} else {
+		targetIndex := -1
+		for i, stage := range stages {
+			if strings.EqualFold(stage.name, target) {
+				targetIndex = i
+			}
+		}
+		if targetIndex < 0 {
+			return nil, fmt.Errorf("the target stage '%s' could not be found in the Dockerfile", target)
+		}
+		var visit func(index int)
+		visit = func(index int) {
+			if selected[index] {
+				return
+			}
+			selected[index] = true
+			for _, dependency := range stages[index].dependencies {
+				visit(dependency)
+			}
+		}
+		visit(targetIndex)
+	}
+	removed := make(map[int]bool)
+	for i, stage := range stages {
+		included := len(include) == 0
+		for _, pattern := range include {
+			if included, err = path.Match(pattern, stage.name); err != nil || included {
+				break
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, pattern := range exclude {
+			var excluded bool
+			if excluded, err = path.Match(pattern, stage.name); err != nil {
+				return nil, err
+			}
+			included = included && !excluded
+		}
+		if !selected[i] || !included {
+			logger.InfoWith("ignoring build stage").
+				String("stage", stage.name).
+				Write()
+			removed[i] = true
+		}
+	}
+	stage := -1
+	for _, inst := range instructions {
+		if inst.command == DockerFromInstruction {
+			stage++
+		}
+		if stage < 0 || !removed[stage] {
+			result = append(result, inst)
+		} else if kept, ok := inst.keepArgs(keptArgs); ok {
+			result = append(result, kept)
+		}
+	}
+	return result, nil
+}
+
+// keepArgs returns a copy of an ARG instruction that only declares the given ARGs. ok is false if the instruction
+// declares none of them.
+func (i instruction) keepArgs(names []string) (kept instruction, ok bool) {
+	if i.command != DockerArgInstruction {
+		return kept, false
+	}
+	kept = i
+	kept.args = nil
+	for _, arg := range i.args {
+		if name, _, _ := strings.Cut(arg, ArgEquation); containsString(names, name) {
+			kept.args = append(kept.args, arg)
+		}
+	}
+	return kept, len(kept.args) > 0
+}
+
+// parseInstruction parses a single logical Dockerfile line starting at the given line number.
+func parseInstruction(text string, line int, escape string) (inst instruction, err error) {
+	text = strings.TrimSpace(text)
+	command, rest := text, ""
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		command, rest = text[:i], strings.TrimSpace(text[i:])
+	}
+	inst = instruction{command: strings.ToUpper(command), flags: make(map[string]string), line: line}
+	words, err := splitWords(rest, escape)
+	if err != nil {
+		if inst.command == DockerFromInstruction || inst.command == DockerArgInstruction {
+			return inst, fmt.Errorf("line %d: %v", line, err)
+		}
+		words = strings.Fields(rest)
+	}
+	for len(words) > 0 && strings.HasPrefix(words[0], "--") {
+		name, value, _ := strings.Cut(strings.TrimPrefix(words[0], "--"), ArgEquation)
+		inst.flags[name] = value
+		words = words[1:]
+	}
+	inst.args = words
+	if inst.command == DockerFromInstruction {
+		if len(inst.args) == 0 {
+			return inst, fmt.Errorf("line %d: the FROM instruction requires a base image", line)
+		}
+		if len(inst.args) > 1 && (!strings.EqualFold(inst.args[1], DockerAsKeyword) || len(inst.args) != 3) {
+			return inst, fmt.Errorf("line %d: the FROM instruction must have the format "+
+				"'FROM [--platform=<platform>] <image> [AS <name>]'", line)
+		}
+	}
+	return inst, nil
+}
+
+// splitWords splits the given text by whitespace while respecting quotes and the given escape character.
+func splitWords(text string, escape string) (words []string, err error) {
+	var word strings.Builder
+	var inWord, escaped bool
+	var quote rune
+	for _, r := range text {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case string(r) == escape && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote %q", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// substituteArgs expands the ARG references in FROM, ARG, ENV, and LABEL instructions the way `docker build` does.
+// FROM instructions only see the ARGs declared before the first FROM instruction. ENV variables are visible to the
+// subsequent instructions of the same stage.
+// The given build args override the default values of the declared ARGs.
+func substituteArgs(instructions []instruction, buildArgs map[string]string) []instruction {
+	global := make(map[string]string)
+	scope := global
+	result := make([]instruction, len(instructions))
+	for i, inst := range instructions {
+		switch inst.command {
+		case DockerFromInstruction:
+			inst.args = append([]string{expandArgs(inst.image(), global, inst.line)}, inst.args[1:]...)
+			scope = make(map[string]string)
+		case DockerArgInstruction:
+			args := make([]string, len(inst.args))
+			for n, arg := range inst.args {
+				name, value, hasDefault := strings.Cut(arg, ArgEquation)
+				if buildArg, ok := buildArgs[name]; ok {
+					value = buildArg
+				} else if hasDefault {
+					value = expandArgs(value, scope, inst.line)
+				} else if globalValue, ok := global[name]; ok {
+					value = globalValue
+				}
+				scope[name] = value
+				if value != "" {
+					args[n] = name + ArgEquation + value
+				} else {
+					args[n] = name
+				}
+			}
+			inst.args = args
+		case DockerEnvInstruction, DockerLabelInstruction:
+			pairs := inst.pairs()
+			args := make([]string, len(pairs))
+			for n, pair := range pairs {
+				value := expandVariables(pair.value, scope, inst.line, false)
+				if inst.command == DockerEnvInstruction {
+					scope[pair.key] = value
+				}
+				args[n] = pair.key + ArgEquation + value
+			}
+			inst.args = args
+		}
+		result[i] = inst
+	}
+	return result
+}
+
+// expandArgs replaces the `$VAR`, `${VAR}`, `${VAR:-default}`, and `${VAR:+alternative}` references in the given
+// text by the values of the given ARG scope. Without colon, the default and alternative forms only check if the ARG
+// is declared, not if it is empty. Undeclared ARGs are replaced by an empty string.
+func expandArgs(text string, scope map[string]string, line int) string {
+	return expandVariables(text, scope, line, true)
+}
+
+// expandVariables replaces the variable references in the given text like expandArgs.
+// If warn is false, references to undeclared variables (e.g., environment variables of the base image) are replaced
+// silently.
+func expandVariables(text string, scope map[string]string, line int, warn bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '$' || i+1 == len(text) {
+			builder.WriteByte(text[i])
+			continue
+		}
+		var name, modifier, word string
+		var orEmpty bool
+		if text[i+1] == '{' {
+			end := matchingBrace(text, i+1)
+			if end < 0 {
+				builder.WriteString(text[i:])
+				break
+			}
+			name = text[i+2 : end]
+			if index := strings.IndexAny(name, ":-+"); index >= 0 {
+				name, modifier = name[:index], name[index:]
+				orEmpty = strings.HasPrefix(modifier, ":")
+				if modifier = strings.TrimPrefix(modifier, ":"); modifier != "" {
+					modifier, word = modifier[:1], modifier[1:]
+				}
+			}
+			i = end
+		} else {
+			end := i + 1
+			for end < len(text) && isArgNameChar(text[end], end == i+1) {
+				end++
+			}
+			if end == i+1 {
+				builder.WriteByte(text[i])
+				continue
+			}
+			name = text[i+1 : end]
+			i = end - 1
+		}
+		value, declared := scope[name]
+		set := declared && (!orEmpty || value != "")
+		switch modifier {
+		case "-":
+			if !set {
+				value = expandVariables(word, scope, line, warn)
+			}
+		case "+":
+			value = ""
+			if set {
+				value = expandVariables(word, scope, line, warn)
+			}
+		default:
+			if !declared && warn {
+				logger.WarnWith("the referenced ARG is not declared").
+					String("arg", name).
+					Int("line", line).
+					Write()
+			}
+		}
+		builder.WriteString(value)
+	}
+	return builder.String()
+}
+
+// matchingBrace returns the index of the closing brace that matches the opening brace at the given index.
+// It returns -1 if the brace is not closed.
+func matchingBrace(text string, open int) int {
+	var depth int
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isArgNameChar marks if the given character may be used in an ARG name.
+// Digits are not allowed as first character.
+func isArgNameChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
@@ -0,0 +1,375 @@
+package tupliplib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseDockerfile(t *testing.T) {
+	type want struct {
+		command  string
+		args     []string
+		flags    map[string]string
+		line     int
+		stageRef bool
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []want
+		wantErr bool
+	}{
+		{
+			name:    "Empty",
+			content: "",
+		},
+		{
+			name:    "Lowercase Keywords",
+			content: "from golang:1.22 as builder",
+			want: []want{
+				{command: "FROM", args: []string{"golang:1.22", "as", "builder"}, line: 1},
+			},
+		},
+		{
+			name:    "Uppercase Stage Keyword And Platform Flag",
+			content: "FROM --platform=$BUILDPLATFORM golang:1.22 AS builder",
+			want: []want{
+				{
+					command: "FROM",
+					args:    []string{"golang:1.22", "AS", "builder"},
+					flags:   map[string]string{"platform": "$BUILDPLATFORM"},
+					line:    1,
+				},
+			},
+		},
+		{
+			name:    "Comments And Continuations",
+			content: "# comment\nFROM \\\n  # inner comment\n  alpine:3.8 \\\n  AS base\n\nARG VERSION=\"1.0\"",
+			want: []want{
+				{command: "FROM", args: []string{"alpine:3.8", "AS", "base"}, line: 2},
+				{command: "ARG", args: []string{"VERSION=1.0"}, line: 7},
+			},
+		},
+		{
+			name:    "Escape Directive",
+			content: "# escape=`\nFROM alpine:3.8 `\n  AS base",
+			want: []want{
+				{command: "FROM", args: []string{"alpine:3.8", "AS", "base"}, line: 2},
+			},
+		},
+		{
+			name:    "Stage References",
+			content: "FROM golang:1.22 AS Builder\nFROM builder AS test\nFROM test\nFROM scratch",
+			want: []want{
+				{command: "FROM", args: []string{"golang:1.22", "AS", "Builder"}, line: 1},
+				{command: "FROM", args: []string{"builder", "AS", "test"}, line: 2, stageRef: true},
+				{command: "FROM", args: []string{"test"}, line: 3, stageRef: true},
+				{command: "FROM", args: []string{"scratch"}, line: 4},
+			},
+		},
+		{
+			name:    "Forward Stage Reference",
+			content: "FROM test\nFROM golang:1.22 AS test",
+			want: []want{
+				{command: "FROM", args: []string{"test"}, line: 1},
+				{command: "FROM", args: []string{"golang:1.22", "AS", "test"}, line: 2},
+			},
+		},
+		{
+			name:    "Tuplip Directives",
+			content: "# tuplip: add-latest\nFROM golang:1.22 \\\n  # tuplip: ignore\n  AS builder",
+			want: []want{
+				{command: "tuplip:", args: []string{"add-latest"}, line: 1},
+				{command: "FROM", args: []string{"golang:1.22", "AS", "builder"}, line: 2},
+			},
+		},
+		{
+			name:    "Missing Base Image",
+			content: "FROM --platform=linux/amd64",
+			wantErr: true,
+		},
+		{
+			name:    "Missing Stage Name",
+			content: "FROM golang:1.22 AS",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated Quote",
+			content: "ARG VERSION=\"1.0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructions, err := parseDockerfile(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDockerfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			linkStages(instructions)
+			if len(instructions) != len(tt.want) {
+				t.Fatalf("parseDockerfile() = %v, want %v", instructions, tt.want)
+			}
+			for i, inst := range instructions {
+				w := tt.want[i]
+				if w.flags == nil {
+					w.flags = map[string]string{}
+				}
+				if inst.command != w.command || !cmp.Equal(inst.args, w.args) || !cmp.Equal(inst.flags, w.flags) ||
+					inst.line != w.line || inst.stageRef != w.stageRef {
+					t.Errorf("parseDockerfile()[%d] = %+v, want %+v", i, inst, w)
+				}
+			}
+		})
+	}
+}
+
+func Test_expandArgs(t *testing.T) {
+	scope := map[string]string{"VERSION": "1.2.3", "EMPTY": "", "ALIAS": "alpine"}
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "No Reference", text: "golang:1.22", want: "golang:1.22"},
+		{name: "Simple Reference", text: "golang:$VERSION-alpine", want: "golang:1.2.3-alpine"},
+		{name: "Braced Reference", text: "golang:${VERSION}-${ALIAS}3.8", want: "golang:1.2.3-alpine3.8"},
+		{name: "Undeclared Reference", text: "golang:${UNKNOWN}1.0", want: "golang:1.0"},
+		{name: "Default For Undeclared", text: "${UNKNOWN:-1.0}", want: "1.0"},
+		{name: "Default For Empty", text: "${EMPTY:-1.0}", want: "1.0"},
+		{name: "Default Without Colon For Empty", text: "${EMPTY-1.0}", want: ""},
+		{name: "Default For Declared", text: "${VERSION:-1.0}", want: "1.2.3"},
+		{name: "Nested Default", text: "${UNKNOWN:-${VERSION}}", want: "1.2.3"},
+		{name: "Alternative For Declared", text: "${VERSION:+set}", want: "set"},
+		{name: "Alternative For Empty", text: "${EMPTY:+set}", want: ""},
+		{name: "Trailing Dollar", text: "foo$", want: "foo$"},
+		{name: "Unterminated Brace", text: "foo${VERSION", want: "foo${VERSION"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandArgs(tt.text, scope, 1); got != tt.want {
+				t.Errorf("expandArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTuplip_FromFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		file           string
+		options        FileOptions
+		conventions    Conventions
+		wantRepository string
+		wantVectors    []string
+		wantDigests    map[string]string
+		wantOptions    map[string]VectorOptions
+		wantErr        bool
+	}{
+		{
+			name:           "Multi Stage",
+			file:           "../../test/MultiStage.Dockerfile",
+			wantRepository: "gofunky/multi",
+			wantVectors:    []string{"golang:1.22.3", "alpine:3.19", "docker:18.09.0", "_:2.4"},
+		},
+		{
+			name:           "ARG Defaults",
+			file:           "../../test/Args.Dockerfile",
+			wantRepository: "gofunky/args",
+			wantVectors:    []string{"golang:1.22.3", "alpine:3.19", "docker:18.09.0"},
+		},
+		{
+			name: "Build Args",
+			file: "../../test/Args.Dockerfile",
+			options: FileOptions{BuildArgs: map[string]string{
+				"GO_VERSION":     "1.23.0",
+				"VERSION":        "3.1",
+				"ORGANIZATION":   "other",
+				"DOCKER_VERSION": "20.10",
+			}},
+			wantRepository: "other/args",
+			wantVectors:    []string{"golang:1.23.0", "alpine:3.19", "docker:18.09.0", "_:3.1"},
+		},
+		{
+			name:           "Root Version Overrides Build Arg",
+			file:           "../../test/Args.Dockerfile",
+			options:        FileOptions{RootVersion: "4.0", BuildArgs: map[string]string{"VERSION": "3.1"}},
+			wantRepository: "gofunky/args",
+			wantVectors:    []string{"golang:1.22.3", "alpine:3.19", "docker:18.09.0", "_:4.0"},
+		},
+		{
+			name:        "All Stages",
+			file:        "../../test/Targets.Dockerfile",
+			wantVectors: []string{"golang:1.22.3", "node:20.11.0", "alpine:3.19", "debian:12", "_:1.0"},
+		},
+		{
+			name:        "Target With Copied Stages",
+			file:        "../../test/Targets.Dockerfile",
+			options:     FileOptions{Target: "release"},
+			wantVectors: []string{"golang:1.22.3", "node:20.11.0", "alpine:3.19", "_:1.0"},
+		},
+		{
+			name:        "Target With Base Stage",
+			file:        "../../test/Targets.Dockerfile",
+			options:     FileOptions{Target: "TEST"},
+			wantVectors: []string{"golang:1.22.3", "_:1.0"},
+		},
+		{
+			name:        "Included Stages",
+			file:        "../../test/Targets.Dockerfile",
+			options:     FileOptions{IncludeStages: []string{"rel*", "front*"}},
+			wantVectors: []string{"node:20.11.0", "alpine:3.19", "_:1.0"},
+		},
+		{
+			name:        "Excluded Stages",
+			file:        "../../test/Targets.Dockerfile",
+			options:     FileOptions{Target: "release", ExcludeStages: []string{"front*"}},
+			wantVectors: []string{"golang:1.22.3", "alpine:3.19", "_:1.0"},
+		},
+		{
+			name:    "Unknown Target",
+			file:    "../../test/Targets.Dockerfile",
+			options: FileOptions{Target: "unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Pattern",
+			file:    "../../test/Targets.Dockerfile",
+			options: FileOptions{ExcludeStages: []string{"["}},
+			wantErr: true,
+		},
+		{
+			name:        "Registry References And Digests",
+			file:        "../../test/References.Dockerfile",
+			wantVectors: []string{"golang:1.22.3", "alpine:3.19", "node:20.11.0", "debian"},
+			wantDigests: map[string]string{
+				"golang": "sha256:" + strings.Repeat("a", 64),
+				"debian": "sha256:" + strings.Repeat("0123456789abcdef", 4),
+			},
+		},
+		{
+			name:           "Environment Without Key Rules",
+			file:           "../../test/Env.Dockerfile",
+			wantRepository: "gofunky/env",
+			wantVectors:    []string{"node:20.11.0", "alpine", "alpine:3.19"},
+		},
+		{
+			name:           "Environment With Key Rules",
+			file:           "../../test/Env.Dockerfile",
+			options:        FileOptions{VectorKeys: []string{"GOLANG_VERSION=go", "*_VERSION"}},
+			wantRepository: "gofunky/env",
+			wantVectors: []string{"node:20.11.0", "alpine", "alpine:3.19", "yarn:1.22.19", "npm:10.2.4",
+				"go:1.22.3"},
+		},
+		{
+			name:           "Environment With Version Label",
+			file:           "../../test/Env.Dockerfile",
+			options:        FileOptions{VersionLabel: true},
+			wantRepository: "gofunky/env",
+			wantVectors:    []string{"node:20.11.0", "alpine", "alpine:3.19", "_:2.0.1"},
+		},
+		{
+			name:           "Version ARG Precedes Version Label",
+			file:           "../../test/Env.Dockerfile",
+			options:        FileOptions{VersionLabel: true, BuildArgs: map[string]string{"VERSION": "3.0"}},
+			wantRepository: "gofunky/env",
+			wantVectors:    []string{"node:20.11.0", "alpine", "alpine:3.19", "_:3.0"},
+		},
+		{
+			name:           "Key Rules Of Target Stage",
+			file:           "../../test/Env.Dockerfile",
+			options:        FileOptions{Target: "build", VectorKeys: []string{"*_VERSION"}, VersionLabel: true},
+			wantRepository: "gofunky/env",
+			wantVectors:    []string{"node:20.11.0", "alpine", "yarn:1.22.19", "npm:10.2.4"},
+		},
+		{
+			name:    "Invalid Key Rule Alias",
+			file:    "../../test/Env.Dockerfile",
+			options: FileOptions{VectorKeys: []string{"GOLANG_VERSION=go-lang"}},
+			wantErr: true,
+		},
+		{
+			name:        "Default Conventions",
+			file:        "../../test/Conventions.Dockerfile",
+			wantVectors: []string{"golang:1.22.3", "node:20.11.0", "alpine:3.19", "_:base-image"},
+		},
+		{
+			name: "Custom Conventions",
+			file: "../../test/Conventions.Dockerfile",
+			conventions: Conventions{
+				VersionArg:         "RELEASE",
+				RepositoryArg:      "TARGET_REPOSITORY",
+				IgnoredAliasPrefix: "skip_",
+				VectorArgPrefix:    "TUPLIP_",
+			},
+			wantRepository: "gofunky/conventions",
+			wantVectors:    []string{"golang:1.22.3", "alpine:3.19", "slim", "_:1.4.2"},
+		},
+		{
+			name:        "Directives",
+			file:        "../../test/Directives.Dockerfile",
+			wantVectors: []string{"go:1.22.3", "alpine:3.19", "_:1.2.0"},
+			wantOptions: map[string]VectorOptions{
+				"go":     {ExcludeMajor: true},
+				"alpine": {ExcludeMinor: true, ExcludeBase: true},
+			},
+		},
+		{
+			name:        "Extraction Heuristic",
+			file:        "../../test/Extraction.Dockerfile",
+			wantVectors: []string{"temurin:17.0.10_7", "jre", "jammy", "gradle:jdk17", "_:1.0.0"},
+		},
+		{
+			name:        "Extraction Presets",
+			file:        "../../test/Extraction.Dockerfile",
+			conventions: Conventions{ExtractionPresets: true},
+			wantVectors: []string{"temurin:17.0.10", "jre", "jammy", "gradle", "jdk:17", "_:1.0.0"},
+		},
+		{
+			name:    "Unresolved Image Name",
+			file:    "../../test/UnsetArg.Dockerfile",
+			wantErr: true,
+		},
+		{
+			name:    "Missing File",
+			file:    "../../test/Missing.Dockerfile",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := (&Tuplip{Conventions: tt.conventions}).FromFile(tt.file, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if src.Repository != tt.wantRepository {
+				t.Errorf("Tuplip.FromFile() repository = %v, want %v", src.Repository, tt.wantRepository)
+			}
+			if tt.wantDigests == nil {
+				tt.wantDigests = map[string]string{}
+			}
+			if !cmp.Equal(src.Digests, tt.wantDigests) {
+				t.Errorf("Tuplip.FromFile() digests = %v, want %v", src.Digests, tt.wantDigests)
+			}
+			if tt.wantOptions == nil {
+				tt.wantOptions = map[string]VectorOptions{}
+			}
+			if !cmp.Equal(src.options, tt.wantOptions) {
+				t.Errorf("Tuplip.FromFile() options = %v, want %v", src.options, tt.wantOptions)
+			}
+			gotVectors := collectVectors(t, src)
+			wantVectors := mapset.NewSet()
+			for _, v := range tt.wantVectors {
+				wantVectors.Add(v)
+			}
+			if !gotVectors.Equal(wantVectors) {
+				t.Errorf("Tuplip.FromFile() = %v, want %v", gotVectors, wantVectors)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package tupliplib
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+const (
+	// EnvPrefix is the default prefix of the environment variables that contain tag vectors.
+	EnvPrefix = "TUPLIP_"
+	// envRootName is the name of the environment variable that contains the root tag vector version.
+	envRootName = "ROOT"
+	// envAliasName is the name of the environment variable that contains the alias tag vectors.
+	envAliasName = "ALIAS"
+	// envVectorPrefix is the name prefix of the environment variables that contain dependency tag vectors.
+	envVectorPrefix = "VECTOR_"
+)
+
+// interpolationPattern matches a variable reference in a tag vector in the format `${VAR}` or `${VAR:-default}`.
+var interpolationPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?}`)
+
+// EnvOptions contain the parameters for reading tag vectors from environment variables.
+type EnvOptions struct {
+	// Prefix is the prefix of the environment variables. It defaults to EnvPrefix.
+	Prefix string `name:"env-prefix" default:"TUPLIP_" help:"the prefix of the environment variables that contain the tag vectors"`
+	// VectorMap maps the names of the vector variables without prefix (e.g., `NODE_JS`) to vector aliases.
+	// An empty alias ignores the variable. Unmapped names are converted to lower-case aliases.
+	VectorMap []string `name:"env-map" placeholder:"NAME=ALIAS" help:"map the name of a vector variable to a vector alias, or ignore the variable if the alias is empty"`
+}
+
+// prefix returns the prefix of the environment variables.
+func (o EnvOptions) prefix() string {
+	if o.Prefix == "" {
+		return EnvPrefix
+	}
+	return o.Prefix
+}
+
+// FromEnv builds a tuplip source from the given environment variables in the format `KEY=VALUE` (e.g., os.Environ).
+// With the default prefix, `TUPLIP_ROOT` contains the root tag vector version, `TUPLIP_ALIAS` contains comma- or
+// space-separated alias tag vectors, and each `TUPLIP_VECTOR_<NAME>` contains the version of a dependency tag vector
+// with the lower-case name as alias. Vector variables without value yield alias tag vectors.
+// The source is empty if no variables with the prefix are set.
+func (t *Tuplip) FromEnv(environ []string, options EnvOptions) (source *TuplipSource, err error) {
+	prefix := options.prefix()
+	logger.InfoWith("queueing read from environment").
+		String("prefix", prefix).
+		Write()
+	mapping, err := parseAliasMap(options.VectorMap, "NAME=ALIAS")
+	if err != nil {
+		return nil, err
+	}
+	var root string
+	var aliases, vectors []string
+	for _, variable := range environ {
+		key, value, _ := strings.Cut(variable, ArgEquation)
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch name := strings.TrimPrefix(key, prefix); {
+		case name == envRootName:
+			root = value
+		case name == envAliasName:
+			aliases = strings.FieldsFunc(value, func(r rune) bool {
+				return r == ',' || unicode.IsSpace(r)
+			})
+		case strings.HasPrefix(name, envVectorPrefix):
+			name = strings.TrimPrefix(name, envVectorPrefix)
+			alias, ok := mapping[name]
+			if !ok {
+				alias = normalizeAlias(name)
+			}
+			if alias == "" {
+				continue
+			}
+			if value != "" {
+				alias += VersionSeparator + value
+			}
+			vectors = append(vectors, alias)
+		}
+	}
+	sort.Strings(vectors)
+	vectors = append(vectors, aliases...)
+	if root != "" {
+		vectors = append([]string{WildcardDependency + VersionSeparator + root}, vectors...)
+	}
+	if len(vectors) == 0 {
+		logger.WarnWith("no tag vectors could be found in the environment").
+			String("prefix", prefix).
+			Write()
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm}, nil
+}
+
+// interpolate replaces the variable references in the given tag vector by the values of the environment variables.
+// A reference has the format `${VAR}`, or `${VAR:-default}` to fall back to a default if the variable is unset or
+// empty. References to unset variables without default fail.
+func interpolate(vector string) (result string, err error) {
+	result = interpolationPattern.ReplaceAllStringFunc(vector, func(reference string) string {
+		groups := interpolationPattern.FindStringSubmatch(reference)
+		value, ok := os.LookupEnv(groups[1])
+		if value == "" && strings.Contains(reference, ":-") {
+			return groups[2]
+		}
+		if !ok && err == nil {
+			err = fmt.Errorf("the tag vector '%s' references the unset variable '%s'", vector, groups[1])
+		}
+		return value
+	})
+	return result, err
+}
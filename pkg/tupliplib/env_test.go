@@ -0,0 +1,111 @@
+package tupliplib
+
+import (
+	"testing"
+
+	"github.com/gofunky/automi/collectors"
+	"github.com/gofunky/pyraset/v2"
+)
+
+func TestTuplip_FromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		environ     []string
+		options     EnvOptions
+		wantVectors []interface{}
+		wantErr     bool
+	}{
+		{
+			name: "Default Prefix",
+			environ: []string{
+				"PATH=/usr/bin",
+				"TUPLIP_ROOT=1.2.3",
+				"TUPLIP_VECTOR_ALPINE=3.19",
+				"TUPLIP_VECTOR_NODE_JS=20.11.0",
+				"TUPLIP_VECTOR_DEBUG=",
+				"TUPLIP_ALIAS=slim, edge",
+				"VECTOR_GO=1.22",
+			},
+			wantVectors: []interface{}{"_:1.2.3", "alpine:3.19", "node_js:20.11.0", "debug", "slim", "edge"},
+		},
+		{
+			name:        "Custom Prefix",
+			environ:     []string{"TUPLIP_ROOT=1.2.3", "CI_TAG_ROOT=2.0", "CI_TAG_VECTOR_GO=1.22"},
+			options:     EnvOptions{Prefix: "CI_TAG_"},
+			wantVectors: []interface{}{"_:2.0", "go:1.22"},
+		},
+		{
+			name:        "Vector Mapping",
+			environ:     []string{"TUPLIP_VECTOR_NODE_JS=20.11.0", "TUPLIP_VECTOR_DEBUG=", "TUPLIP_VECTOR_GOLANG=1.22"},
+			options:     EnvOptions{VectorMap: []string{"NODE_JS=node", "DEBUG="}},
+			wantVectors: []interface{}{"node:20.11.0", "golang:1.22"},
+		},
+		{
+			name:        "Empty",
+			environ:     []string{"PATH=/usr/bin", "TUPLIP_ROOT="},
+			wantVectors: []interface{}{},
+		},
+		{
+			name:    "Invalid Mapping",
+			environ: []string{"TUPLIP_VECTOR_NODE_JS=20.11.0"},
+			options: EnvOptions{VectorMap: []string{"NODE_JS=node-js"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).FromEnv(tt.environ, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.FromEnv() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
+
+func Test_interpolate(t *testing.T) {
+	t.Setenv("TUPLIP_TEST_ALPINE", "3.19")
+	t.Setenv("TUPLIP_TEST_EMPTY", "")
+	tests := []struct {
+		vector  string
+		want    string
+		wantErr bool
+	}{
+		{vector: "alpine:${TUPLIP_TEST_ALPINE}", want: "alpine:3.19"},
+		{vector: "alpine:$TUPLIP_TEST_ALPINE", want: "alpine:$TUPLIP_TEST_ALPINE"},
+		{vector: "${TUPLIP_TEST_EMPTY}", want: ""},
+		{vector: "_:${TUPLIP_TEST_EMPTY:-1.0}", want: "_:1.0"},
+		{vector: "_:${TUPLIP_TEST_UNSET:-1.0}", want: "_:1.0"},
+		{vector: "_:${TUPLIP_TEST_UNSET}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vector, func(t *testing.T) {
+			got, err := interpolate(tt.vector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("interpolate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want && !tt.wantErr {
+				t.Errorf("interpolate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTuplip_FromSlice_Interpolation(t *testing.T) {
+	t.Setenv("TUPLIP_TEST_ALPINE", "3.19")
+	src := new(Tuplip).FromSlice([]string{"alpine:${TUPLIP_TEST_ALPINE}", "${TUPLIP_TEST_UNSET:-}", "foo"})
+	want := mapset.NewSet("alpine:3.19", "foo")
+	if got := collectVectors(t, src); !got.Equal(want) {
+		t.Errorf("Tuplip.FromSlice() = %v, want %v", got, want)
+	}
+	src = new(Tuplip).FromSlice([]string{"alpine:${TUPLIP_TEST_UNSET}"})
+	src.stream.Into(collectors.Slice())
+	if err := <-src.stream.Open(); err == nil {
+		t.Errorf("Tuplip.FromSlice() with an unset variable did not fail")
+	}
+}
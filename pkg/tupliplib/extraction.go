@@ -0,0 +1,159 @@
+package tupliplib
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+// ExtractionRule extracts the tag vectors from the tags of the matching images using a regular expression.
+type ExtractionRule struct {
+	// Image is a glob pattern that matches the image names (i.e., the last path component) the rule applies to.
+	Image string
+	// Pattern is the regular expression that has to match the whole tag.
+	// The group `version` contains the version of the image vector. Groups with names starting with `alias` yield
+	// alias vectors from their matches. All other named groups yield dependency vectors with the group name as alias
+	// and their match as version, or alias vectors with the group name if their match is empty.
+	Pattern *regexp.Regexp
+}
+
+// NewExtractionRule compiles an extraction rule for the images matching the given glob pattern.
+// The given regular expression is anchored to match the whole tag.
+func NewExtractionRule(image string, pattern string) (rule ExtractionRule, err error) {
+	if _, err = path.Match(image, ""); err != nil {
+		return rule, fmt.Errorf("invalid image pattern '%s' in extraction rule: %v", image, err)
+	}
+	compiled, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return rule, fmt.Errorf("invalid regular expression in extraction rule for '%s': %v", image, err)
+	}
+	return ExtractionRule{Image: image, Pattern: compiled}, nil
+}
+
+// UnmarshalText parses an extraction rule in the format `IMAGE=REGEX`.
+func (r *ExtractionRule) UnmarshalText(text []byte) (err error) {
+	image, pattern, ok := strings.Cut(string(text), ArgEquation)
+	if !ok || image == "" {
+		return fmt.Errorf("the extraction rule '%s' must have the format 'IMAGE=REGEX'", text)
+	}
+	*r, err = NewExtractionRule(image, pattern)
+	return err
+}
+
+// String returns the extraction rule in the format `IMAGE=REGEX`.
+func (r ExtractionRule) String() string {
+	if r.Pattern == nil {
+		return r.Image
+	}
+	pattern := strings.TrimSuffix(strings.TrimPrefix(r.Pattern.String(), "^(?:"), ")$")
+	return r.Image + ArgEquation + pattern
+}
+
+// extract derives the tag vectors from the given tag if the rule applies to the given image name.
+// The given alias is used for the image vector. ok is false if the rule does not match.
+func (r ExtractionRule) extract(image string, tag string, alias string) (vectors []string, ok bool) {
+	if r.Pattern == nil {
+		return nil, false
+	}
+	if matched, _ := path.Match(r.Image, image); !matched {
+		return nil, false
+	}
+	indices := r.Pattern.FindStringSubmatchIndex(tag)
+	if indices == nil {
+		return nil, false
+	}
+	vectors = []string{alias}
+	for i, name := range r.Pattern.SubexpNames() {
+		if name == "" || indices[2*i] < 0 {
+			continue
+		}
+		match := tag[indices[2*i]:indices[2*i+1]]
+		switch {
+		case name == "version":
+			if match != "" {
+				vectors[0] = alias + VersionSeparator + match
+			}
+		case strings.HasPrefix(name, "alias"):
+			if match != "" {
+				vectors = append(vectors, match)
+			}
+		case match != "":
+			vectors = append(vectors, name+VersionSeparator+match)
+		default:
+			vectors = append(vectors, name)
+		}
+	}
+	return vectors, true
+}
+
+// Patterns of common tag suffixes that are shared by the extraction presets.
+const (
+	alpineSuffix   = `-alpine(?P<alpine>(?:\d+(?:\.\d+)*)?)`
+	debianSuffix   = `-(?P<alias_debian>trixie|bookworm|bullseye|buster)`
+	ubuntuSuffix   = `-(?P<alias_ubuntu>noble|jammy|focal)`
+	numericVersion = `\d+(?:\.\d+)*`
+)
+
+// ExtractionPresets are the built-in extraction rules for popular official images.
+var ExtractionPresets = []ExtractionRule{
+	mustExtractionRule("python", `(?P<version>`+numericVersion+`(?:(?:a|b|rc)\d+)?)(?:-(?P<alias_slim>slim))?`+
+		`(?:`+debianSuffix+`|`+alpineSuffix+`)?`),
+	mustExtractionRule("ruby", `(?P<version>`+numericVersion+`(?:-preview\d+)?)(?:-(?P<alias_slim>slim))?`+
+		`(?:`+debianSuffix+`|`+alpineSuffix+`)?`),
+	mustExtractionRule("node", `(?P<version>`+numericVersion+`)(?:`+debianSuffix+`)?(?:-(?P<alias_slim>slim))?`+
+		`(?:`+alpineSuffix+`)?`),
+	mustExtractionRule("golang", `(?P<version>`+numericVersion+`(?:rc\d+)?)(?:`+debianSuffix+`|`+alpineSuffix+`)?`),
+	mustExtractionRule("openjdk", `(?P<version>`+numericVersion+`)(?:-(?P<alias_type>jdk|jre))?`+
+		`(?:-(?P<alias_slim>slim))?(?:`+debianSuffix+`|`+alpineSuffix+`)?`),
+	mustExtractionRule("eclipse-temurin", `(?P<version>`+numericVersion+`)(?:_\d+)?(?:-(?P<alias_type>jdk|jre))?`+
+		`(?:`+ubuntuSuffix+`|`+alpineSuffix+`)?`),
+	mustExtractionRule("gradle", `(?:(?P<version>`+numericVersion+`)-)?jdk(?P<jdk>\d+)`+
+		`(?:`+ubuntuSuffix+`|`+alpineSuffix+`)?`),
+}
+
+// mustExtractionRule compiles an extraction rule and panics if it is invalid.
+func mustExtractionRule(image string, pattern string) ExtractionRule {
+	rule, err := NewExtractionRule(image, pattern)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// extract derives the tag vectors from the given tag using the first matching extraction rule.
+// The extraction rules of the conventions are tried before the presets. ok is false if no rule matches.
+func (c Conventions) extract(image string, tag string, alias string) (vectors []string, ok bool) {
+	rules := c.ExtractionRules
+	if c.ExtractionPresets {
+		rules = append(append([]ExtractionRule{}, rules...), ExtractionPresets...)
+	}
+	for _, rule := range rules {
+		if vectors, ok = rule.extract(image, tag, alias); ok {
+			return vectors, true
+		}
+	}
+	return nil, false
+}
+
+// tagSet splits the given remote tag of the given repository into the set of its tag vectors in tag format.
+// If an extraction rule matches, its image vector is the root tag vector. Otherwise, the tag is split by dashes.
+func (c Conventions) tagSet(repository string, tag string) mapset.Set {
+	vectorSet := mapset.NewSet()
+	_, repositoryPath := splitDomain(repository)
+	name := repositoryPath[strings.LastIndex(repositoryPath, RepositorySeparator)+1:]
+	if vectors, ok := c.extract(name, tag, WildcardDependency); ok {
+		for _, v := range vectors {
+			if v != WildcardDependency {
+				vectorSet.Add(withoutColons(withoutWildcard(v)))
+			}
+		}
+		return vectorSet
+	}
+	for _, v := range strings.Split(tag, DockerTagSeparator) {
+		vectorSet.Add(v)
+	}
+	return vectorSet
+}
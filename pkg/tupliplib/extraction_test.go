@@ -0,0 +1,178 @@
+package tupliplib
+
+import (
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConventions_extract(t *testing.T) {
+	custom, err := NewExtractionRule("*", `(?P<version>\d+)-(?P<alias_kind>full|lite)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		conventions Conventions
+		image       string
+		tag         string
+		wantVectors []string
+		wantOk      bool
+	}{
+		{
+			name:  "Presets Disabled",
+			image: "python", tag: "3.12.1-slim-bookworm",
+		},
+		{
+			name:        "Python Slim Debian",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "python", tag: "3.12.1-slim-bookworm",
+			wantVectors: []string{"python:3.12.1", "slim", "bookworm"},
+			wantOk:      true,
+		},
+		{
+			name:        "Python Release Candidate On Alpine",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "python", tag: "3.13.0rc1-alpine3.19",
+			wantVectors: []string{"python:3.13.0rc1", "alpine:3.19"},
+			wantOk:      true,
+		},
+		{
+			name:        "Node Unversioned Alpine",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "node", tag: "20.11-bookworm-slim",
+			wantVectors: []string{"node:20.11", "bookworm", "slim"},
+			wantOk:      true,
+		},
+		{
+			name:        "Golang Alpine Without Version",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "golang", tag: "1.22-alpine",
+			wantVectors: []string{"golang:1.22", "alpine"},
+			wantOk:      true,
+		},
+		{
+			name:        "OpenJDK Type And Alpine",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "openjdk", tag: "17-jdk-alpine3.19",
+			wantVectors: []string{"openjdk:17", "jdk", "alpine:3.19"},
+			wantOk:      true,
+		},
+		{
+			name:        "Temurin Build Number",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "eclipse-temurin", tag: "17.0.10_7-jre-jammy",
+			wantVectors: []string{"eclipse-temurin:17.0.10", "jre", "jammy"},
+			wantOk:      true,
+		},
+		{
+			name:        "Gradle Without Version",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "gradle", tag: "jdk17",
+			wantVectors: []string{"gradle", "jdk:17"},
+			wantOk:      true,
+		},
+		{
+			name:        "Unknown Preset Tag",
+			conventions: Conventions{ExtractionPresets: true},
+			image:       "python", tag: "latest",
+		},
+		{
+			name:        "Custom Rule Precedes Presets",
+			conventions: Conventions{ExtractionRules: []ExtractionRule{custom}, ExtractionPresets: true},
+			image:       "python", tag: "3-full",
+			wantVectors: []string{"python:3", "full"},
+			wantOk:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVectors, gotOk := tt.conventions.extract(tt.image, tt.tag, tt.image)
+			if !cmp.Equal(gotVectors, tt.wantVectors) || gotOk != tt.wantOk {
+				t.Errorf("Conventions.extract() = %v, %v, want %v, %v", gotVectors, gotOk, tt.wantVectors, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestConventions_toTagVector(t *testing.T) {
+	major, err := NewExtractionRule("*", `(?P<version>\d+)(\.\d+)*`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conventions := Conventions{ExtractionRules: []ExtractionRule{major}}
+	tests := []struct {
+		name string
+		inst instruction
+		want []string
+	}{
+		{
+			name: "Base Image",
+			inst: instruction{command: DockerFromInstruction, args: []string{"python:3.12.1"}},
+			want: []string{"python:3"},
+		},
+		{
+			name: "Root Version",
+			inst: rootInstruction("1.2.3"),
+			want: []string{"_:1.2.3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conventions.toTagVector(tt.inst); !cmp.Equal(got, tt.want) {
+				t.Errorf("Conventions.toTagVector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractionRule_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Valid", text: "my-*=(?P<version>[0-9.]+),x", want: "my-*=(?P<version>[0-9.]+),x"},
+		{name: "Missing Image", text: "=(?P<version>.*)", wantErr: true},
+		{name: "Missing Separator", text: "python", wantErr: true},
+		{name: "Invalid Image Pattern", text: "[=.*", wantErr: true},
+		{name: "Invalid Regular Expression", text: "python=(?P<version>", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rule ExtractionRule
+			err := rule.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractionRule.UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && rule.String() != tt.want {
+				t.Errorf("ExtractionRule.String() = %v, want %v", rule.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConventions_tagSet(t *testing.T) {
+	conventions := Conventions{ExtractionPresets: true}
+	tests := []struct {
+		name       string
+		repository string
+		tag        string
+		want       []interface{}
+	}{
+		{name: "Heuristic", repository: "gofunky/git", tag: "2.4-alpine3.8", want: []interface{}{"2.4", "alpine3.8"}},
+		{name: "Preset", repository: "library/python", tag: "3.12-slim-bookworm",
+			want: []interface{}{"3.12", "slim", "bookworm"}},
+		{name: "Preset Without Root Version", repository: "docker.io/library/gradle", tag: "jdk17",
+			want: []interface{}{"jdk17"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conventions.tagSet(tt.repository, tt.tag); !got.Equal(mapset.NewSet(tt.want...)) {
+				t.Errorf("Conventions.tagSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
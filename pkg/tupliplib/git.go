@@ -0,0 +1,142 @@
+package tupliplib
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+const (
+	// GitDirtyAlias is the alias vector that marks a git working tree with uncommitted changes.
+	GitDirtyAlias = "dirty"
+	// GitDistanceAlias is the prefix of the alias vector that contains the number of commits since the version tag.
+	// The number is part of the alias (e.g., `commits5`), so that it is no version that is expanded to short versions.
+	GitDistanceAlias = "commits"
+	// gitTagDecoration is the prefix of tag names in the ref decorations of `git log`.
+	gitTagDecoration = "tag: "
+)
+
+// GitOptions contain the parameters for reading tag vectors from a git repository.
+type GitOptions struct {
+	// TagPrefix limits the version tags to the ones with the given prefix. The prefix is removed from the version.
+	TagPrefix string `placeholder:"PREFIX" help:"only consider the version tags with the given prefix (e.g., 'app/')"`
+	// ExcludeBranches omit the branch vector for the branches matching the given glob patterns.
+	ExcludeBranches []string `name:"exclude-branch" placeholder:"PATTERN" help:"omit the branch vector for the branches matching the given glob patterns"`
+	// Dirty adds the GitDirtyAlias vector if the working tree has uncommitted changes.
+	Dirty bool `help:"add a 'dirty' alias vector if the working tree has uncommitted changes"`
+	// Distance adds the number of commits since the version tag as alias vector with the GitDistanceAlias prefix.
+	Distance bool `help:"add the number of commits since the version tag as alias vector (e.g., 'commits5')"`
+}
+
+// FromGit builds a tuplip source from the local git repository in the given directory.
+// The root tag vector version is the nearest semantic version tag on the first-parent history of HEAD.
+// The checked-out branch yields an alias vector with its sanitized name. Detached checkouts have no branch vector.
+func (t *Tuplip) FromGit(src string, options GitOptions) (source *TuplipSource, err error) {
+	if src == "" {
+		src = "."
+	}
+	logger.InfoWith("queueing read from git repository").
+		String("directory", src).
+		Write()
+	if _, err = exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("the git source requires a git executable: %v", err)
+	}
+	var vectors []string
+	version, distance, err := gitVersion(src, options.TagPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		vectors = append(vectors, WildcardDependency+VersionSeparator+version)
+	} else {
+		logger.WarnWith("no semantic version tag could be found in the git history").
+			String("directory", src).
+			Write()
+	}
+	branch, err := runGit(src, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if alias := normalizeAlias(branch); branch != "HEAD" && alias != "" {
+		excluded := false
+		for _, pattern := range options.ExcludeBranches {
+			if excluded, err = path.Match(pattern, branch); err != nil {
+				return nil, err
+			} else if excluded {
+				break
+			}
+		}
+		if !excluded {
+			vectors = append(vectors, alias)
+		}
+	}
+	if options.Dirty {
+		status, err := runGit(src, "status", "--porcelain")
+		if err != nil {
+			return nil, err
+		}
+		if status != "" {
+			vectors = append(vectors, GitDirtyAlias)
+		}
+	}
+	if options.Distance && version != "" && distance > 0 {
+		vectors = append(vectors, GitDistanceAlias+strconv.Itoa(distance))
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("the git repository in '%s' does not yield any tag vectors", src)
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm}, nil
+}
+
+// gitVersion finds the nearest semantic version tag with the given prefix on the first-parent history of HEAD.
+// The distance is the number of commits since the tagged commit. If a commit has multiple version tags, the highest
+// version is used. Pre-release and build metadata tags are skipped, so that they cannot overwrite the tags of the
+// stable releases. The version is empty if no version tag could be found.
+func gitVersion(dir string, prefix string) (version string, distance int, err error) {
+	out, err := runGit(dir, "log", "--first-parent", "--format=%D", "--decorate-refs=refs/tags/")
+	if err != nil {
+		return "", 0, err
+	}
+	for distance, line := range strings.Split(out, "\n") {
+		var highest semver.Version
+		for _, ref := range strings.Split(line, ", ") {
+			name := strings.TrimPrefix(ref, gitTagDecoration)
+			if name == ref || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			candidate, parsed, ok := tagVersion(strings.TrimPrefix(name, prefix))
+			if ok && (len(parsed.Pre) > 0 || len(parsed.Build) > 0) {
+				logger.WarnWith("skipping pre-release or build metadata tag").
+					String("tag", name).
+					Write()
+			} else if ok && (version == "" || parsed.GT(highest)) {
+				version, highest = candidate, parsed
+			}
+		}
+		if version != "" {
+			return version, distance, nil
+		}
+	}
+	return "", 0, nil
+}
+
+// runGit executes git with the given arguments in the given directory and returns its output without trailing line
+// breaks.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	logger.InfoWith("execute").
+		String("args", strings.Join(cmd.Args, " ")).
+		Write()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,106 @@
+package tupliplib
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+// gitFixture creates a git repository with the given commands in a temporary directory.
+func gitFixture(t *testing.T, commands ...[]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	for _, args := range append([][]string{{"init", "-q", "-b", "main"}}, commands...) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=tuplip", "-c",
+			"user.email=tuplip@example.com"}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "HOME="+dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestTuplip_FromGit(t *testing.T) {
+	commit := []string{"commit", "-q", "--allow-empty", "-m", "commit"}
+	tagged := gitFixture(t,
+		commit, []string{"tag", "v1.0.0"},
+		commit, []string{"tag", "v1.1.0"}, []string{"tag", "v1.1.0-rc.1"}, []string{"tag", "app/v3.0"},
+		[]string{"checkout", "-q", "-b", "feature/New-UI"},
+		commit, commit,
+	)
+	if err := os.WriteFile(filepath.Join(tagged, "file"), []byte("change"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prerelease := gitFixture(t,
+		commit, []string{"tag", "v1.0.0"},
+		commit, []string{"tag", "v2.0.0-rc.1"}, []string{"tag", "v2.0.0+build.1"},
+	)
+	untagged := gitFixture(t, commit, []string{"checkout", "-q", "--detach"})
+	tests := []struct {
+		name        string
+		dir         string
+		options     GitOptions
+		wantVectors []interface{}
+		wantErr     bool
+	}{
+		{
+			name:        "Nearest Version And Branch",
+			dir:         tagged,
+			wantVectors: []interface{}{"_:1.1.0", "feature_new_ui"},
+		},
+		{
+			name:        "Tag Prefix",
+			dir:         tagged,
+			options:     GitOptions{TagPrefix: "app/"},
+			wantVectors: []interface{}{"_:3.0", "feature_new_ui"},
+		},
+		{
+			name:        "Dirty And Distance",
+			dir:         tagged,
+			options:     GitOptions{Dirty: true, Distance: true},
+			wantVectors: []interface{}{"_:1.1.0", "feature_new_ui", "dirty", "commits2"},
+		},
+		{
+			name:        "Skipped Pre-Release",
+			dir:         prerelease,
+			wantVectors: []interface{}{"_:1.0.0", "main"},
+		},
+		{
+			name:        "Excluded Branch",
+			dir:         tagged,
+			options:     GitOptions{ExcludeBranches: []string{"feature/*"}},
+			wantVectors: []interface{}{"_:1.1.0"},
+		},
+		{
+			name:    "Detached Without Version",
+			dir:     untagged,
+			wantErr: true,
+		},
+		{
+			name:    "No Repository",
+			dir:     t.TempDir(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).FromGit(tt.dir, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromGit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.FromGit() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package tupliplib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// GoModFile is the default Go module file.
+const GoModFile = "go.mod"
+
+// GoModOptions contain the parameters for reading tag vectors from a go.mod file.
+type GoModOptions struct {
+	// GoAlias is the alias of the Go version vector. It defaults to `golang` like the official image.
+	GoAlias string `name:"go-alias" default:"golang" help:"the alias of the Go version vector"`
+	// Modules are glob patterns of the required modules that yield dependency vectors, optionally followed by an alias
+	// after an equation sign. The alias defaults to the last path element of the module without major version suffix.
+	Modules []string `name:"module" placeholder:"MODULE[=ALIAS]" help:"add the versions of the required modules matching the glob pattern as dependency vectors"`
+}
+
+// goAlias returns the alias of the Go version vector.
+func (o GoModOptions) goAlias() string {
+	if o.GoAlias == "" {
+		return "golang"
+	}
+	return o.GoAlias
+}
+
+// FromGoMod builds a tuplip source from a go.mod file.
+// The Go version is taken from the toolchain directive, or from the go directive if no toolchain is given.
+// The required modules that match the allow-list of the given options yield dependency vectors with their versions.
+// Replaced modules use the version of their replacement.
+// Pseudo-versions and pre-releases are skipped with a warning, since they are no releases of the module.
+func (t *Tuplip) FromGoMod(src string, options GoModOptions) (source *TuplipSource, err error) {
+	if src == "" {
+		src = GoModFile
+	}
+	logger.InfoWith("queueing read from go.mod").
+		String("file", src).
+		Write()
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(absSrc)
+	if err != nil {
+		return nil, err
+	}
+	file, err := modfile.Parse(src, content, nil)
+	if err != nil {
+		return nil, err
+	}
+	var vectors []string
+	if file.Toolchain != nil {
+		vectors = append(vectors, options.goAlias()+VersionSeparator+strings.TrimPrefix(file.Toolchain.Name, "go"))
+	} else if file.Go != nil {
+		vectors = append(vectors, options.goAlias()+VersionSeparator+file.Go.Version)
+	}
+	replaced := make(map[string]string)
+	for _, replace := range file.Replace {
+		if replace.New.Version != "" {
+			replaced[replace.Old.Path] = replace.New.Version
+		}
+	}
+	for _, entry := range options.Modules {
+		pattern, alias, _ := strings.Cut(entry, ArgEquation)
+		if alias != "" && normalizeAlias(alias) != alias {
+			return nil, fmt.Errorf("the module mapping '%s' has an invalid alias '%s'", entry, alias)
+		}
+		var found bool
+		for _, require := range file.Require {
+			if matched, err := path.Match(pattern, require.Mod.Path); err != nil {
+				return nil, fmt.Errorf("invalid module pattern '%s': %v", pattern, err)
+			} else if !matched {
+				continue
+			}
+			found = true
+			version := require.Mod.Version
+			if replacement, ok := replaced[require.Mod.Path]; ok {
+				version = replacement
+			}
+			if module.IsPseudoVersion(version) || semver.Prerelease(version) != "" {
+				logger.WarnWith("skipping pseudo-version or pre-release of the module").
+					String("module", require.Mod.Path).
+					String("version", version).
+					Write()
+				continue
+			}
+			moduleAlias := alias
+			if moduleAlias == "" {
+				moduleAlias = moduleName(require.Mod.Path)
+			}
+			vectors = append(vectors, moduleAlias+VersionSeparator+vectorVersion(version))
+		}
+		if !found {
+			logger.WarnWith("no required module matches the pattern").
+				String("module", pattern).
+				Write()
+		}
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("the go.mod file '%s' does not yield any tag vectors", src)
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm}, nil
+}
+
+// moduleName derives the vector alias from the given module path.
+// It is the last path element without the major version suffix (e.g., `semver` for `github.com/blang/semver/v4`).
+func moduleName(modulePath string) string {
+	prefix, _, _ := module.SplitPathVersion(modulePath)
+	return normalizeAlias(path.Base(prefix))
+}
+
+// vectorVersion converts the given module or tool version to a vector version.
+// The `v` prefix and the build metadata (e.g., `+incompatible`) are removed since they are not valid in Docker tags.
+func vectorVersion(version string) string {
+	version, _, _ = strings.Cut(strings.TrimPrefix(version, "v"), "+")
+	return version
+}
@@ -0,0 +1,77 @@
+package tupliplib
+
+import (
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+func TestTuplip_FromGoMod(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		options     GoModOptions
+		wantVectors []interface{}
+		wantErr     bool
+	}{
+		{
+			name:        "Toolchain",
+			file:        "../../test/service.go.mod",
+			wantVectors: []interface{}{"golang:1.23.1"},
+		},
+		{
+			name: "Module Allow-List",
+			file: "../../test/service.go.mod",
+			options: GoModOptions{Modules: []string{
+				"github.com/blang/semver/v4",
+				"github.com/alecthomas/kong=cli",
+				"github.com/gofunky/*",
+				"github.com/unknown/*",
+			}},
+			wantVectors: []interface{}{"golang:1.23.1", "semver:4.0.0", "cli:0.9.0", "automi:0.2.0"},
+		},
+		{
+			name:        "Incompatible Module",
+			file:        "../../test/service.go.mod",
+			options:     GoModOptions{Modules: []string{"github.com/blang/semver"}},
+			wantVectors: []interface{}{"golang:1.23.1", "semver:3.5.1"},
+		},
+		{
+			name:        "Pseudo-Version And Pre-Release",
+			file:        "../../test/service.go.mod",
+			options:     GoModOptions{Modules: []string{"github.com/example/*"}},
+			wantVectors: []interface{}{"golang:1.23.1"},
+		},
+		{
+			name:        "Go Directive With Custom Alias",
+			file:        "../../test/minimal.go.mod",
+			options:     GoModOptions{GoAlias: "go"},
+			wantVectors: []interface{}{"go:1.21"},
+		},
+		{
+			name:    "Invalid Alias",
+			file:    "../../test/service.go.mod",
+			options: GoModOptions{Modules: []string{"github.com/alecthomas/kong=kong-cli"}},
+			wantErr: true,
+		},
+		{
+			name:    "Missing File",
+			file:    "../../test/missing.go.mod",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).FromGoMod(tt.file, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromGoMod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.FromGoMod() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
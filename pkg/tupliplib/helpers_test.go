@@ -0,0 +1,54 @@
+package tupliplib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofunky/automi/collectors"
+	"github.com/gofunky/automi/stream"
+	"github.com/gofunky/pyraset/v2"
+)
+
+// collectVectors drains the stream of the given source into a set.
+func collectVectors(t *testing.T, src *TuplipSource) mapset.Set {
+	t.Helper()
+	return collectStream(t, src.stream)
+}
+
+// collectStream drains the given stream into a set.
+func collectStream(t *testing.T, stm *stream.Stream) mapset.Set {
+	t.Helper()
+	collector := collectors.Slice()
+	stm.Into(collector)
+	select {
+	case err := <-stm.Open():
+		if err != nil {
+			t.Fatalf("stream error = %v", err)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Waited too long ...")
+	}
+	return mapset.NewSet(collector.Get()...)
+}
+
+// wantSource is the expected repository and the expected tag vectors of a tuplip source.
+type wantSource struct {
+	repository string
+	vectors    []interface{}
+}
+
+// checkSources compares the repositories and the tag vectors of the given sources with the expected ones.
+func checkSources(t *testing.T, got []*TuplipSource, want []wantSource) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d sources, want %d", len(got), len(want))
+	}
+	for i, src := range got {
+		if src.Repository != want[i].repository {
+			t.Errorf("source %d repository = %v, want %v", i, src.Repository, want[i].repository)
+		}
+		if vectors := collectVectors(t, src); !vectors.Equal(mapset.NewSet(want[i].vectors...)) {
+			t.Errorf("source %d = %v, want %v", i, vectors, want[i].vectors)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package tupliplib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+// ImageEnvKeys are the glob patterns of the ENV keys of an image that yield dependency vectors if no patterns are
+// specified.
+var ImageEnvKeys = []string{"*_VERSION"}
+
+// ImageOptions contain the parameters for reading tag vectors from a local image.
+type ImageOptions struct {
+	// Labels derive dependency vectors from the labels matching the given glob patterns.
+	// The alias is derived from the wildcard part of the key unless it is given after an equation sign.
+	Labels []string `name:"image-label" placeholder:"PATTERN[=ALIAS]" help:"derive dependency vectors from the image labels matching the given glob patterns"`
+	// EnvKeys derive dependency vectors from the ENV keys matching the given glob patterns.
+	// The alias is derived from the wildcard part of the key unless it is given after an equation sign.
+	// They default to the ImageEnvKeys.
+	EnvKeys []string `name:"image-env" placeholder:"PATTERN[=ALIAS]" help:"derive dependency vectors from the image ENV keys matching the given glob patterns (default: *_VERSION)"`
+}
+
+// imageInspection is the part of the output of `docker image inspect` that is relevant for tag vectors.
+type imageInspection struct {
+	RepoTags []string `json:"RepoTags"`
+	Config   struct {
+		Env    []string          `json:"Env"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+}
+
+// FromImage builds a tuplip source from a local image by inspecting it with the Docker engine.
+// The OCIVersionLabel yields the root tag vector. The labels and the ENV keys that match the rules of the given
+// options yield dependency vectors. Their values are converted to vector versions like the ones of the go.mod files,
+// and values that don't start with a digit (e.g., `lts`) are skipped with a warning.
+// The repository is taken from the repository tags of the image.
+// The inspection is also executed in simulation mode since it does not modify any images.
+func (t *Tuplip) FromImage(image string, options ImageOptions) (source *TuplipSource, err error) {
+	logger.InfoWith("queueing read from image").
+		String("image", image).
+		Write()
+	if _, err = exec.LookPath("docker"); err != nil {
+		return nil, fmt.Errorf("the image source requires a docker executable: %v", err)
+	}
+	cmd := exec.Command("docker", "image", "inspect", image)
+	logger.InfoWith("execute").
+		String("args", strings.Join(cmd.Args, " ")).
+		Write()
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("docker image inspect failed: %v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return t.fromInspection(image, out, options)
+}
+
+// fromInspection builds a tuplip source from the given output of `docker image inspect` for the given image.
+func (t *Tuplip) fromInspection(image string, content []byte, options ImageOptions) (source *TuplipSource, err error) {
+	var inspections []imageInspection
+	if err = json.Unmarshal(content, &inspections); err != nil {
+		return nil, fmt.Errorf("the inspection of the image '%s' could not be read: %v", image, err)
+	}
+	if len(inspections) != 1 {
+		return nil, fmt.Errorf("the inspection of the image '%s' contains %d images", image, len(inspections))
+	}
+	inspection := inspections[0]
+	labelRules, err := parseKeyRules(options.Labels)
+	if err != nil {
+		return nil, err
+	}
+	envKeys := options.EnvKeys
+	if len(envKeys) == 0 {
+		envKeys = ImageEnvKeys
+	}
+	envRules, err := parseKeyRules(envKeys)
+	if err != nil {
+		return nil, err
+	}
+	var vectors []string
+	included := make(map[string]bool)
+	include := func(alias string, key string, raw string) {
+		version := vectorVersion(strings.TrimSpace(raw))
+		if alias == "" || version == "" || included[alias] {
+			return
+		}
+		if !unicode.IsDigit(rune(version[0])) {
+			logger.WarnWith("skipping unsupported image version").
+				String("key", key).
+				String("version", raw).
+				Write()
+			return
+		}
+		included[alias] = true
+		vectors = append(vectors, alias+VersionSeparator+version)
+	}
+	include(WildcardDependency, OCIVersionLabel, inspection.Config.Labels[OCIVersionLabel])
+	var labels []string
+	for label := range inspection.Config.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		for _, rule := range labelRules {
+			if alias, ok := rule.match(label); ok {
+				include(alias, label, inspection.Config.Labels[label])
+				break
+			}
+		}
+	}
+	for _, env := range inspection.Config.Env {
+		key, value, _ := strings.Cut(env, ArgEquation)
+		for _, rule := range envRules {
+			if alias, ok := rule.match(key); ok {
+				include(alias, key, value)
+				break
+			}
+		}
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("the image '%s' does not yield any tag vectors", image)
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm, Repository: inspectedRepository(image, inspection.RepoTags)}, nil
+}
+
+// inspectedRepository returns the repository of the given repository tags that matches the given image reference,
+// or the repository of the first repository tag otherwise. It is empty if the image has no repository tags.
+func inspectedRepository(image string, repoTags []string) string {
+	if len(repoTags) == 0 {
+		return ""
+	}
+	wanted := parseReference(image).repository()
+	for _, repoTag := range repoTags {
+		if repository := parseReference(repoTag).repository(); repository == wanted {
+			return repository
+		}
+	}
+	return parseReference(repoTags[0]).repository()
+}
@@ -0,0 +1,113 @@
+package tupliplib
+
+import (
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+// testInspection is the output of `docker image inspect` for a test image.
+const testInspection = `[
+  {
+    "Id": "sha256:4f5c8d1b4e2e9b0b6a0d5b1f2b1c9e8f7d6c5b4a3928170f6e5d4c3b2a1f0e9d",
+    "RepoTags": ["ghcr.io/gofunky/app:1.2.0", "gofunky/app:latest"],
+    "Config": {
+      "Env": [
+        "PATH=/usr/local/bin:/usr/bin:/bin",
+        "NODE_VERSION=20.11.0",
+        "YARN_VERSION=1.22.19"
+      ],
+      "Labels": {
+        "org.opencontainers.image.version": "1.2.0",
+        "org.opencontainers.image.source": "https://github.com/gofunky/app",
+        "com.example.alpine.version": "3.19"
+      }
+    }
+  }
+]`
+
+func TestTuplip_fromInspection(t *testing.T) {
+	tests := []struct {
+		name           string
+		image          string
+		content        string
+		options        ImageOptions
+		wantVectors    []interface{}
+		wantRepository string
+		wantErr        bool
+	}{
+		{
+			name:           "Default Rules",
+			image:          "gofunky/app:latest",
+			content:        testInspection,
+			wantVectors:    []interface{}{"_:1.2.0", "node:20.11.0", "yarn:1.22.19"},
+			wantRepository: "gofunky/app",
+		},
+		{
+			name:    "Label and ENV Rules",
+			image:   "4f5c8d1b4e2e",
+			content: testInspection,
+			options: ImageOptions{
+				Labels:  []string{"com.example.*.version"},
+				EnvKeys: []string{"NODE_VERSION=nodejs"},
+			},
+			wantVectors:    []interface{}{"_:1.2.0", "alpine:3.19", "nodejs:20.11.0"},
+			wantRepository: "ghcr.io/gofunky/app",
+		},
+		{
+			name:        "Without Repository Tags",
+			image:       "4f5c8d1b4e2e",
+			content:     `[{"RepoTags": [], "Config": {"Env": ["GOLANG_VERSION=1.22.3"]}}]`,
+			wantVectors: []interface{}{"golang:1.22.3"},
+		},
+		{
+			name:  "Version Conversion",
+			image: "4f5c8d1b4e2e",
+			content: `[{"Config": {"Env": ["PYTHON_VERSION=v3.12.1", "JAVA_VERSION=jdk-17.0.10+7", "NODE_VERSION=lts"], ` +
+				`"Labels": {"org.opencontainers.image.version": "v1.2.0+build.5"}}}]`,
+			wantVectors: []interface{}{"_:1.2.0", "python:3.12.1"},
+		},
+		{
+			name:    "Unsupported Root Version",
+			image:   "gofunky/app:latest",
+			content: `[{"Config": {"Labels": {"org.opencontainers.image.version": "main"}}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "No Vectors",
+			image:   "gofunky/app:latest",
+			content: `[{"Config": {"Env": ["PATH=/bin"]}}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Rule",
+			image:   "gofunky/app:latest",
+			content: testInspection,
+			options: ImageOptions{Labels: []string{"=alpine"}},
+			wantErr: true,
+		},
+		{
+			name:    "Missing Image",
+			image:   "gofunky/app:latest",
+			content: `[]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).fromInspection(tt.image, []byte(tt.content), tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.fromInspection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if src.Repository != tt.wantRepository {
+				t.Errorf("Tuplip.fromInspection() repository = %v, want %v", src.Repository, tt.wantRepository)
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.fromInspection() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
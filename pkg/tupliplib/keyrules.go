@@ -0,0 +1,143 @@
+package tupliplib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// keyRule derives dependency vectors from the ARG, ENV, and LABEL keys that match its pattern.
+type keyRule struct {
+	// pattern matches the whole key. Each wildcard of the glob pattern is captured by a group.
+	pattern *regexp.Regexp
+	// alias is the fixed alias of the derived vectors. If empty, the alias is derived from the wildcard captures.
+	alias string
+}
+
+// parseKeyRules parses the given key rules in the format `PATTERN[=ALIAS]`.
+// The pattern is a glob pattern whose `*` wildcards match any sequence of characters.
+func parseKeyRules(rules []string) (result []keyRule, err error) {
+	for _, rule := range rules {
+		pattern, alias, _ := strings.Cut(rule, ArgEquation)
+		if pattern == "" {
+			return nil, fmt.Errorf("the key rule '%s' requires a pattern", rule)
+		}
+		if alias != "" && normalizeAlias(alias) != alias {
+			return nil, fmt.Errorf("the key rule '%s' has an invalid alias '%s'", rule, alias)
+		}
+		parts := strings.Split(pattern, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		result = append(result, keyRule{
+			pattern: regexp.MustCompile("^" + strings.Join(parts, "(.*)") + "$"),
+			alias:   alias,
+		})
+	}
+	return result, nil
+}
+
+// match returns the alias that the given key is mapped to. ok is false if the rule does not match.
+func (r keyRule) match(key string) (alias string, ok bool) {
+	captures := r.pattern.FindStringSubmatch(key)
+	if captures == nil {
+		return "", false
+	}
+	if r.alias != "" {
+		return r.alias, true
+	}
+	return normalizeAlias(strings.Join(captures[1:], "")), true
+}
+
+// normalizeAlias converts the given text to a valid vector alias.
+// Letters are converted to lower-case and characters other than letters, digits, dots, and underscores are replaced by
+// underscores. Leading and trailing dots and underscores are removed.
+func normalizeAlias(text string) string {
+	alias := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, text)
+	return strings.Trim(alias, "._")
+}
+
+// parseAliasMap parses the given mappings of names to vector aliases in the format `NAME=ALIAS`.
+// The given format describes the mapping in error messages. An empty alias is allowed.
+func parseAliasMap(entries []string, format string) (mapping map[string]string, err error) {
+	mapping = make(map[string]string)
+	for _, entry := range entries {
+		name, alias, ok := strings.Cut(entry, ArgEquation)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("the mapping '%s' must have the format '%s'", entry, format)
+		}
+		if normalizeAlias(alias) != alias {
+			return nil, fmt.Errorf("the mapping '%s' has an invalid alias '%s'", entry, alias)
+		}
+		mapping[name] = alias
+	}
+	return mapping, nil
+}
+
+// toVectors converts the given Dockerfile instruction to tag vectors.
+// FROM instructions of base images yield their tag vectors and the version ARG yields the root tag vector unless
+// withoutRoot is set. ARGs with the vector ARG prefix yield their tag vectors. Keys of ARG, ENV, and LABEL
+// instructions yield a dependency vector with their value as version if they match any of the given rules.
+// The first matching rule wins.
+func (c Conventions) toVectors(rules []keyRule, withoutRoot bool) func(inst instruction) []string {
+	return func(inst instruction) (vectors []string) {
+		if isBaseImage(inst) {
+			return c.toTagVector(inst)
+		}
+		vectors = make([]string, 0)
+		for _, pair := range inst.pairs() {
+			if inst.command == DockerArgInstruction && pair.key == c.versionArg() {
+				if !withoutRoot && pair.value != "" {
+					vectors = append(vectors, WildcardDependency+VersionSeparator+pair.value)
+				}
+				continue
+			}
+			if inst.command == DockerArgInstruction {
+				if vector, ok := c.vectorArg(pair); ok {
+					if vector != "" {
+						vectors = append(vectors, vector)
+					}
+					continue
+				}
+			}
+			value := strings.TrimSpace(pair.value)
+			if value == "" {
+				continue
+			}
+			for _, rule := range rules {
+				if alias, ok := rule.match(pair.key); ok {
+					if alias != "" && !c.isIgnored(alias) {
+						vectors = append(vectors, alias+VersionSeparator+value)
+					}
+					break
+				}
+			}
+		}
+		return vectors
+	}
+}
+
+// findVersionLabel returns the value of the OCI version label if the given instructions do not define a version ARG
+// value.
+func (c Conventions) findVersionLabel(instructions []instruction) (version string) {
+	for _, inst := range instructions {
+		if value, _ := inst.argValue(c.versionArg()); value != "" {
+			return ""
+		}
+		if inst.command != DockerLabelInstruction {
+			continue
+		}
+		for _, pair := range inst.pairs() {
+			if pair.key == OCIVersionLabel {
+				version = pair.value
+			}
+		}
+	}
+	return version
+}
@@ -0,0 +1,36 @@
+package tupliplib
+
+import (
+	"testing"
+)
+
+func Test_keyRule_match(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		key       string
+		wantAlias string
+		wantOk    bool
+	}{
+		{name: "Suffix Wildcard", rule: "*_VERSION", key: "NODE_VERSION", wantAlias: "node", wantOk: true},
+		{name: "No Match", rule: "*_VERSION", key: "VERSION", wantOk: false},
+		{name: "Fixed Alias", rule: "GOLANG_VERSION=go", key: "GOLANG_VERSION", wantAlias: "go", wantOk: true},
+		{name: "Label Key", rule: "com.example.*-version", key: "com.example.Py-Lib-version", wantAlias: "py_lib",
+			wantOk: true},
+		{name: "Multiple Wildcards", rule: "*_*_VERSION", key: "ALPINE_BASE_VERSION", wantAlias: "alpinebase",
+			wantOk: true},
+		{name: "Meta Characters", rule: "A.B*", key: "AXB_VERSION", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := parseKeyRules([]string{tt.rule})
+			if err != nil {
+				t.Fatalf("parseKeyRules() error = %v", err)
+			}
+			gotAlias, gotOk := rules[0].match(tt.key)
+			if gotAlias != tt.wantAlias || gotOk != tt.wantOk {
+				t.Errorf("keyRule.match() = %v, %v, want %v, %v", gotAlias, gotOk, tt.wantAlias, tt.wantOk)
+			}
+		})
+	}
+}
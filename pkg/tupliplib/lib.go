@@ -7,7 +7,6 @@ import (
 	"io"
 	"io/ioutil"
 	"path/filepath"
-	"strings"
 )
 
 // Tuplip contains the parameters for the Docker tag generation.
@@ -27,39 +26,82 @@ type Tuplip struct {
 	// ExclusiveLatest makes the `latest` tag vector version an exclusive tag if given.
 	// Then, the output will only contain `latest` if the input contains `latest` as root tag vector version.
 	ExclusiveLatest bool `short:"e" help:"make the 'latest' root tag vector version an exclusive tag if given"`
+	// Conventions contain the names that are used to interpret Dockerfiles.
+	Conventions Conventions `kong:"-"`
+}
+
+// FileOptions contains the parameters for reading tag vectors from a Dockerfile.
+type FileOptions struct {
+	// RootVersion overrides the version of the root tag vector from the Dockerfile.
+	RootVersion string `short:"r" help:"override the version of the root tag vector from the Dockerfile"`
+	// BuildArgs override the default values of the ARG instructions in the Dockerfile like `docker build` does.
+	BuildArgs map[string]string `name:"build-arg" placeholder:"KEY=VALUE" help:"override the default value of an ARG instruction in the Dockerfile"`
+	// Target limits the considered build stages to the given target stage and the stages it depends on.
+	Target string `help:"only consider the given target stage and the build stages it depends on"`
+	// IncludeStages limits the considered build stages to the ones with names matching the given glob patterns.
+	IncludeStages []string `name:"include-stage" placeholder:"PATTERN" help:"only consider the build stages with names matching the given glob patterns"`
+	// ExcludeStages ignores the build stages with names matching the given glob patterns.
+	ExcludeStages []string `name:"exclude-stage" placeholder:"PATTERN" help:"ignore the build stages with names matching the given glob patterns"`
+	// VectorKeys derive dependency vectors from the ARG, ENV, and LABEL keys matching the given glob patterns.
+	// The alias is derived from the wildcard part of the key (e.g., `NODE` for `NODE_VERSION` and `*_VERSION`)
+	// unless it is given after an equation sign (e.g., `GOLANG_VERSION=go`).
+	VectorKeys []string `name:"vector-key" placeholder:"PATTERN[=ALIAS]" help:"derive dependency vectors from the ARG, ENV, and LABEL keys matching the given glob patterns"`
+	// VersionLabel uses the OCI version label as the root version if the Dockerfile has no version ARG.
+	VersionLabel bool `help:"use the OCI version label as root version if the Dockerfile has no version ARG"`
 }
 
 // TuplipSource is the intermediary-built Tuplip stream containing only the source parsing steps.
 type TuplipSource struct {
 	tuplip *Tuplip
 	stream *stream.Stream
+	// options are the vector-specific tag generation options mapped by the vector aliases.
+	options map[string]VectorOptions
 	// Repository is the Docker Hub repository of the root tag vector in the format `organization/repository`.
 	Repository string
+	// Digests map the aliases of the dependency vectors to the digests that pin their images if given.
+	Digests map[string]string
+	// file is the Dockerfile that the source was read from if any.
+	file string
+	// image is the name of the manifest image that the source was read from if any.
+	image string
+	// straight marks sources that contain complete tags instead of tag vectors. They are built straightly.
+	straight bool
 }
 
 // FromReader builds a tuplip source from a io.Reader as scanner.
 // The separator is used to split the tag vectors from the same row. It defaults to an empty space.
+// References to environment variables in the tag vectors (e.g., `alpine:${ALPINE_VERSION}`) are interpolated.
 func (t *Tuplip) FromReader(src io.Reader, sep string) *TuplipSource {
 	logger.InfoWith("queueing read from reader").
 		String("separator", sep).
 		Write()
 	stm := stream.New(emitters.Scanner(src, nil))
 	stm.FlatMap(t.splitBySeparator(sep))
+	stm.Map(interpolate)
 	stm.Filter(nonEmpty)
 	return &TuplipSource{tuplip: t, stream: stm}
 }
 
 // FromSlice builds a tuplip source from a slice.
+// References to environment variables in the tag vectors (e.g., `alpine:${ALPINE_VERSION}`) are interpolated.
 func (t *Tuplip) FromSlice(src []string) *TuplipSource {
 	logger.Info("queueing read from slice")
 	stm := stream.New(emitters.Slice(src))
+	stm.Map(interpolate)
 	stm.Filter(nonEmpty)
 	return &TuplipSource{tuplip: t, stream: stm}
 }
 
 // FromFile builds a tuplip source from a Dockerfile.
-// If overrideVersion is non-empty, it overrides the VERSION ARG in the given Dockerfile.
-func (t *Tuplip) FromFile(src string, overrideVersion string) (source *TuplipSource, err error) {
+// ARG references in FROM instructions are resolved from the ARG defaults and the build args of the given options.
+// Base image references that do not match the Docker reference grammar after the resolution fail.
+// The options may limit the considered build stages to a target stage or to stage name patterns.
+// If the options contain a root version, it overrides the version ARG in the given Dockerfile.
+// The options may also derive dependency vectors from ARG, ENV, and LABEL keys.
+// Tuplip directives in comments of the Dockerfile configure the vectors of the following FROM instructions or the
+// tag generation of the whole Dockerfile.
+// The names of the interpreted ARGs and the ignored stage prefix are taken from the tuplip conventions.
+func (t *Tuplip) FromFile(src string, options FileOptions) (source *TuplipSource, err error) {
 	if src == "" {
 		src = Dockerfile
 	}
@@ -74,32 +116,76 @@ func (t *Tuplip) FromFile(src string, overrideVersion string) (source *TuplipSou
 	if err != nil {
 		return nil, err
 	}
-	unixLines := strings.ReplaceAll(string(content), "\r\n", "\n")
-	var lines = strings.Split(unixLines, "\n")
+	instructions, err := parseDockerfile(string(content))
+	if err != nil {
+		return nil, err
+	}
+	instructions, directives, err := resolveDirectives(instructions)
+	if err != nil {
+		return nil, err
+	}
+	instructions = substituteArgs(instructions, options.BuildArgs)
+	stages := linkStages(instructions)
+	instructions, err = selectStages(instructions, stages, options.Target, options.IncludeStages,
+		options.ExcludeStages, []string{t.Conventions.versionArg(), t.Conventions.repositoryArg()})
+	if err != nil {
+		return nil, err
+	}
+	if err = checkBaseImages(instructions); err != nil {
+		return nil, err
+	}
+	rules, err := parseKeyRules(options.VectorKeys)
+	if err != nil {
+		return nil, err
+	}
+	overrideVersion := options.RootVersion
+	if overrideVersion == "" && options.VersionLabel {
+		overrideVersion = t.Conventions.findVersionLabel(instructions)
+	}
 	if overrideVersion != "" {
-		lines = append(lines, WildcardInstruction+overrideVersion)
+		instructions = append(instructions, rootInstruction(overrideVersion))
 	}
-	repository, err := findRepository(lines)
+	repository, err := t.Conventions.findRepository(instructions)
 	if err != nil {
 		return nil, err
 	}
-	stm := stream.New(emitters.Slice(lines))
-	stm.Filter(nonEmpty)
-	stm.Map(transformRootVersion(overrideVersion != ""))
-	stm.Filter(hasPrefix(DockerFromInstruction))
-	stm.FlatMap(toTagVector)
-	source = &TuplipSource{tuplip: t, stream: stm, Repository: repository}
+	stm := stream.New(emitters.Slice(instructions))
+	stm.FlatMap(t.Conventions.toVectors(rules, overrideVersion != ""))
+	tuplip := directives.apply(*t)
+	source = &TuplipSource{
+		tuplip:     &tuplip,
+		stream:     stm,
+		options:    t.Conventions.findOptions(instructions),
+		Repository: repository,
+		Digests:    t.Conventions.findDigests(instructions),
+		file:       absSrc,
+	}
 	return source, nil
 }
 
+// File returns the Dockerfile that the source was read from, or an empty string if the source has no Dockerfile.
+func (s *TuplipSource) File() string {
+	return s.file
+}
+
+// Image returns the name of the manifest image that the source was read from, or an empty string if the source was not
+// read from a manifest. The sources of the target repositories of the same image have the same image name.
+func (s *TuplipSource) Image() string {
+	return s.image
+}
+
 // Build defines a tuplip stream that builds a complete set of Docker tags. The returned stream has no configured sink.
 // requireSemver enables semantic version checks. Short versions are not allowed then.
+// Straight sources that contain complete tags are built straightly.
 func (s *TuplipSource) Build(requireSemver bool) (stream *stream.Stream) {
+	if s.straight {
+		return s.Straight()
+	}
 	logger.InfoWith("queueing build").
 		Bool("require semantic version", requireSemver).
 		Write()
 	stream = s.stream
-	stream.Map(s.tuplip.splitVersion(requireSemver))
+	stream.Map(s.splitVersion(requireSemver))
 	stream.Map(packInSet)
 	stream.Reduce(mapset.NewSet(), mergeSets)
 	stream.Map(power)
@@ -307,7 +307,7 @@ func TestTuplipStream_BuildFromFile_WithoutRepository(t *testing.T) {
 				expectedSet.Add(line)
 			}
 		}
-		tuplipSrc, err := new(Tuplip).FromFile("../../test/WithoutRepository.Dockerfile", "")
+		tuplipSrc, err := new(Tuplip).FromFile("../../test/WithoutRepository.Dockerfile", FileOptions{})
 		if err != nil {
 			t.Errorf("Tuplip.Build() error = %v", err)
 			return
@@ -352,7 +352,7 @@ func TestTuplipStream_BuildFromFile_WithRepository(t *testing.T) {
 				expectedSet.Add(line)
 			}
 		}
-		tuplipSrc, err := new(Tuplip).FromFile("../../test/WithRepository.Dockerfile", "")
+		tuplipSrc, err := new(Tuplip).FromFile("../../test/WithRepository.Dockerfile", FileOptions{})
 		if err != nil {
 			t.Errorf("Tuplip.Build() error = %v", err)
 			return
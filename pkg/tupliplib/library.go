@@ -0,0 +1,372 @@
+package tupliplib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gofunky/automi/collectors"
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+// Fields of the official-images library format.
+const (
+	// LibraryTags is the field of the tags that are specific to a library entry.
+	LibraryTags = "Tags"
+	// LibrarySharedTags is the field of the tags that a library entry shares with other entries.
+	LibrarySharedTags = "SharedTags"
+	// LibraryArchitectures is the field of the architectures of a library entry.
+	LibraryArchitectures = "Architectures"
+	// LibraryGitCommit is the field of the git commit that a library entry is built from.
+	LibraryGitCommit = "GitCommit"
+	// LibraryDirectory is the field of the build context directory of a library entry.
+	LibraryDirectory = "Directory"
+	// LibraryFile is the field of the Dockerfile name of a library entry.
+	LibraryFile = "File"
+	// libraryListSeparator separates the values of list fields.
+	libraryListSeparator = ","
+	// libraryFieldSeparator separates the field name from the value.
+	libraryFieldSeparator = ":"
+)
+
+// LibraryField is a field of the official-images library format that tuplip does not interpret.
+type LibraryField struct {
+	// Name is the name of the field (e.g., `GitRepo`).
+	Name string
+	// Value is the raw value of the field.
+	Value string
+}
+
+// LibraryEntry is a paragraph of the official-images library format.
+type LibraryEntry struct {
+	// Tags are the tags that are specific to the entry.
+	Tags []string
+	// SharedTags are the tags that the entry shares with other entries.
+	SharedTags []string
+	// Architectures are the architectures that the entry is built for.
+	Architectures []string
+	// GitCommit is the git commit that the entry is built from.
+	GitCommit string
+	// Directory is the build context directory in the git repository.
+	Directory string
+	// Fields are the other fields of the entry in their order (e.g., `GitRepo` or `File`).
+	Fields []LibraryField
+}
+
+// Library is a file of the official-images library format (e.g., `library/golang`) that describes the tags of
+// the entries of an image.
+type Library struct {
+	// Header contains the global fields that apply to all entries unless the entries override them.
+	Header LibraryEntry
+	// Entries are the entries of the image.
+	Entries []LibraryEntry
+}
+
+// LibraryOptions contain the parameters for reading tags from an official-images library file.
+type LibraryOptions struct {
+	// Directories limit the read entries to the ones with the given directories.
+	Directories []string `name:"directory" placeholder:"DIRECTORY" help:"only read the library entries with the given directories"`
+}
+
+// ReadLibrary parses an official-images library file. Comments starting with `#` are skipped and indented lines
+// continue the value of the previous field. The first paragraph is the header if it has no tags.
+func ReadLibrary(src io.Reader) (library *Library, err error) {
+	library = new(Library)
+	var entry *LibraryEntry
+	var lastField *string
+	var entries []LibraryEntry
+	scanner := bufio.NewScanner(src)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if trimmed == "" {
+			entry, lastField = nil, nil
+			continue
+		}
+		if text[0] == ' ' || text[0] == '\t' {
+			if lastField == nil {
+				return nil, fmt.Errorf("line %d: the continuation line has no field", line)
+			}
+			*lastField += " " + trimmed
+			continue
+		}
+		name, value, ok := strings.Cut(text, libraryFieldSeparator)
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("line %d: the field must have the format 'Name: Value'", line)
+		}
+		if entry == nil {
+			entries = append(entries, LibraryEntry{})
+			entry = &entries[len(entries)-1]
+		}
+		field := LibraryField{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)}
+		entry.Fields = append(entry.Fields, field)
+		lastField = &entry.Fields[len(entry.Fields)-1].Value
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	for i, raw := range entries {
+		parsed := parseLibraryFields(raw.Fields)
+		if i == 0 && len(parsed.Tags) == 0 && len(parsed.SharedTags) == 0 {
+			library.Header = parsed
+			continue
+		}
+		if len(parsed.Tags) == 0 && len(parsed.SharedTags) == 0 {
+			return nil, fmt.Errorf("the library entry %d has no tags", i+1)
+		}
+		library.Entries = append(library.Entries, parsed)
+	}
+	return library, nil
+}
+
+// parseLibraryFields interprets the known fields of the given raw fields.
+func parseLibraryFields(fields []LibraryField) (entry LibraryEntry) {
+	for _, field := range fields {
+		switch field.Name {
+		case LibraryTags:
+			entry.Tags = append(entry.Tags, splitLibraryList(field.Value)...)
+		case LibrarySharedTags:
+			entry.SharedTags = append(entry.SharedTags, splitLibraryList(field.Value)...)
+		case LibraryArchitectures:
+			entry.Architectures = append(entry.Architectures, splitLibraryList(field.Value)...)
+		case LibraryGitCommit:
+			entry.GitCommit = field.Value
+		case LibraryDirectory:
+			entry.Directory = field.Value
+		default:
+			entry.Fields = append(entry.Fields, field)
+		}
+	}
+	return entry
+}
+
+// splitLibraryList splits the given value of a list field.
+func splitLibraryList(value string) (items []string) {
+	for _, item := range strings.Split(value, libraryListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// inherit returns the entry with the values of the given header for the fields that the entry does not define.
+func (e LibraryEntry) inherit(header LibraryEntry) LibraryEntry {
+	if len(e.Architectures) == 0 {
+		e.Architectures = header.Architectures
+	}
+	if e.GitCommit == "" {
+		e.GitCommit = header.GitCommit
+	}
+	if e.Directory == "" {
+		e.Directory = header.Directory
+	}
+	return e
+}
+
+// write writes the fields of the entry as a paragraph. Empty fields are omitted.
+func (e LibraryEntry) write(writer io.Writer, header bool) (err error) {
+	fields := []LibraryField{
+		{LibraryTags, strings.Join(e.Tags, libraryListSeparator+Space)},
+		{LibrarySharedTags, strings.Join(e.SharedTags, libraryListSeparator+Space)},
+		{LibraryArchitectures, strings.Join(e.Architectures, libraryListSeparator+Space)},
+		{LibraryGitCommit, e.GitCommit},
+		{LibraryDirectory, e.Directory},
+	}
+	if header {
+		fields = append(e.Fields[:len(e.Fields):len(e.Fields)], fields...)
+	} else {
+		fields = append(fields, e.Fields...)
+	}
+	for _, field := range fields {
+		if field.Value == "" {
+			continue
+		}
+		if _, err = fmt.Fprintf(writer, "%s%s %s\n", field.Name, libraryFieldSeparator, field.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write writes the library in the official-images library format.
+// The header is followed by the entries, each separated by a blank line.
+func (l Library) Write(writer io.Writer) (err error) {
+	paragraphs := l.Entries
+	hasHeader := len(l.Header.Fields) > 0 || len(l.Header.Architectures) > 0 || l.Header.GitCommit != "" ||
+		l.Header.Directory != ""
+	if hasHeader {
+		paragraphs = append([]LibraryEntry{l.Header}, paragraphs...)
+	}
+	for i, entry := range paragraphs {
+		if i > 0 {
+			if _, err = io.WriteString(writer, "\n"); err != nil {
+				return err
+			}
+		}
+		if err = entry.write(writer, hasHeader && i == 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildLibrary builds the tags of the given sources and describes each source as an entry of a library with the
+// given header. The tags are built without repository and sorted by their specificity. Sources that yield the same
+// entry, such as the sources of multiple repositories of the same image, are described once. Tags that multiple
+// entries yield are shared tags of all these entries. The directory of an entry is the directory of the Dockerfile of
+// its source relative to the given base directory, or to the top level of its git repository if the base directory is
+// empty. Dockerfiles with other names than `Dockerfile` are described by a File field. The given sources are not
+// modified.
+func BuildLibrary(header LibraryEntry, baseDir string, sources ...*TuplipSource) (library *Library, err error) {
+	logger.InfoWith("building library").
+		Int("sources", len(sources)).
+		Write()
+	library = &Library{Header: header}
+	owners := make(map[string]int)
+	described := make(map[string]bool)
+	for _, original := range sources {
+		src := *original
+		src.Repository = ""
+		stm := src.Build(false)
+		collector := collectors.Slice()
+		stm.Into(collector)
+		if err = <-stm.Open(); err != nil {
+			return nil, err
+		}
+		var entry LibraryEntry
+		for _, item := range collector.Get() {
+			if tag := item.(string); !containsString(entry.Tags, tag) {
+				entry.Tags = append(entry.Tags, tag)
+			}
+		}
+		sort.Sort(SortedTags(entry.Tags))
+		if src.file != "" {
+			if entry.Directory, err = libraryDirectory(src.file, baseDir); err != nil {
+				return nil, err
+			}
+			if name := filepath.Base(src.file); name != Dockerfile {
+				entry.Fields = append(entry.Fields, LibraryField{Name: LibraryFile, Value: name})
+			}
+		}
+		key := fmt.Sprint(entry.Directory, entry.Fields, entry.Tags)
+		if described[key] {
+			continue
+		}
+		described[key] = true
+		for _, tag := range entry.Tags {
+			owners[tag]++
+		}
+		library.Entries = append(library.Entries, entry)
+	}
+	for i, entry := range library.Entries {
+		var tags, shared []string
+		for _, tag := range entry.Tags {
+			if owners[tag] > 1 {
+				shared = append(shared, tag)
+			} else {
+				tags = append(tags, tag)
+			}
+		}
+		library.Entries[i].Tags, library.Entries[i].SharedTags = tags, shared
+	}
+	return library, nil
+}
+
+// libraryDirectory returns the directory of the given Dockerfile relative to the given base directory. If the base
+// directory is empty, it defaults to the top level of the git repository of the Dockerfile, or to the working
+// directory outside of git repositories. Directories outside of the base directory are rejected.
+func libraryDirectory(file string, baseDir string) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return "", err
+	}
+	if baseDir == "" {
+		if baseDir, err = runGit(dir, "rev-parse", "--show-toplevel"); err != nil {
+			logger.WarnWith("the library directory is relative to the working directory").
+				String("reason", err.Error()).
+				Write()
+			if baseDir, err = os.Getwd(); err != nil {
+				return "", err
+			}
+		}
+	}
+	if baseDir, err = filepath.Abs(baseDir); err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(baseDir); err == nil {
+		baseDir = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(baseDir, dir)
+	if err != nil {
+		return "", err
+	}
+	rel = path.Clean(filepath.ToSlash(rel))
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("the directory of the Dockerfile '%s' is outside of the base directory '%s'", file,
+			baseDir)
+	}
+	return rel, nil
+}
+
+// FromLibrary builds a tuplip source for each entry of an official-images library file.
+// The sources contain the complete tags of their entries and are built straightly.
+// The repository is the name of the library file (e.g., `golang` for `library/golang`).
+// If the options contain directories, only the entries with these directories are considered.
+func (t *Tuplip) FromLibrary(src string, options LibraryOptions) (sources []*TuplipSource, err error) {
+	logger.InfoWith("queueing read from library file").
+		String("file", src).
+		Write()
+	file, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	library, err := ReadLibrary(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", src, err)
+	}
+	selected := make(map[string]bool)
+	for _, directory := range options.Directories {
+		selected[path.Clean(directory)] = false
+	}
+	for _, entry := range library.Entries {
+		entry = entry.inherit(library.Header)
+		directory := path.Clean(entry.Directory)
+		if _, ok := selected[directory]; len(options.Directories) > 0 && !ok {
+			continue
+		}
+		selected[directory] = true
+		logger.InfoWith("queueing library entry").
+			String("directory", directory).
+			Write()
+		stm := stream.New(emitters.Slice(append(append([]string{}, entry.Tags...), entry.SharedTags...)))
+		sources = append(sources, &TuplipSource{
+			tuplip:     t,
+			stream:     stm,
+			Repository: filepath.Base(src),
+			straight:   true,
+		})
+	}
+	for directory, found := range selected {
+		if !found {
+			return nil, fmt.Errorf("the directory '%s' could not be found in the library file", directory)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("the library file '%s' has no entries", src)
+	}
+	return sources, nil
+}
@@ -0,0 +1,213 @@
+package tupliplib
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+func TestReadLibrary(t *testing.T) {
+	file, err := os.Open("../../test/library/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	library, err := ReadLibrary(file)
+	if err != nil {
+		t.Fatalf("ReadLibrary() error = %v", err)
+	}
+	want := &Library{
+		Header: LibraryEntry{
+			Architectures: []string{"amd64", "arm64v8"},
+			GitCommit:     "0123456789abcdef0123456789abcdef01234567",
+			Fields: []LibraryField{
+				{"Maintainers", "Jane Doe <jane@example.com> (@jane), John Doe <john@example.com> (@john)"},
+				{"GitRepo", "https://github.com/gofunky/app.git"},
+			},
+		},
+		Entries: []LibraryEntry{
+			{
+				Tags:      []string{"1.2.0-alpine3.19", "1.2-alpine3.19", "1-alpine3.19", "alpine3.19", "alpine"},
+				Directory: "1.2/alpine",
+			},
+			{
+				Tags:          []string{"1.2.0-bookworm", "1.2-bookworm", "1-bookworm", "bookworm"},
+				SharedTags:    []string{"1.2.0", "1.2", "1", "latest"},
+				Architectures: []string{"amd64"},
+				Directory:     "1.2/bookworm",
+				Fields:        []LibraryField{{"File", "Dockerfile.bookworm"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(library, want) {
+		t.Errorf("ReadLibrary() = %+v, want %+v", library, want)
+	}
+}
+
+func TestReadLibrary_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"Continuation Without Field", "  amd64\n"},
+		{"Missing Separator", "Tags 1.0\n"},
+		{"Entry Without Tags", "GitRepo: https://github.com/gofunky/app.git\n\nDirectory: app\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadLibrary(strings.NewReader(tt.content)); err == nil {
+				t.Errorf("ReadLibrary() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLibrary_Write(t *testing.T) {
+	library := Library{
+		Header: LibraryEntry{
+			GitCommit: "abc",
+			Fields:    []LibraryField{{"GitRepo", "https://github.com/gofunky/app.git"}},
+		},
+		Entries: []LibraryEntry{
+			{Tags: []string{"1.0-alpine", "alpine"}, SharedTags: []string{"1.0"}, Directory: "alpine"},
+			{Tags: []string{"1.0-debian"}, Directory: "debian", Fields: []LibraryField{{"File", "Dockerfile.debian"}}},
+		},
+	}
+	want := `GitRepo: https://github.com/gofunky/app.git
+GitCommit: abc
+
+Tags: 1.0-alpine, alpine
+SharedTags: 1.0
+Directory: alpine
+
+Tags: 1.0-debian
+Directory: debian
+File: Dockerfile.debian
+`
+	var buffer bytes.Buffer
+	if err := library.Write(&buffer); err != nil {
+		t.Fatalf("Library.Write() error = %v", err)
+	}
+	if got := buffer.String(); got != want {
+		t.Errorf("Library.Write() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildLibrary(t *testing.T) {
+	tuplip := new(Tuplip)
+	alpine := tuplip.FromSlice([]string{"_:1.0", "alpine"})
+	alpine.Repository = "gofunky/app"
+	duplicate := tuplip.FromSlice([]string{"_:1.0", "alpine"})
+	debian := tuplip.FromSlice([]string{"_:1.0", "debian"})
+	library, err := BuildLibrary(LibraryEntry{GitCommit: "abc"}, "", alpine, duplicate, debian)
+	if err != nil {
+		t.Fatalf("BuildLibrary() error = %v", err)
+	}
+	want := &Library{
+		Header: LibraryEntry{GitCommit: "abc"},
+		Entries: []LibraryEntry{
+			{Tags: []string{"1.0-alpine", "1-alpine", "alpine"}, SharedTags: []string{"1.0", "1"}},
+			{Tags: []string{"1.0-debian", "1-debian", "debian"}, SharedTags: []string{"1.0", "1"}},
+		},
+	}
+	if !reflect.DeepEqual(library, want) {
+		t.Errorf("BuildLibrary() = %+v, want %+v", library, want)
+	}
+	if alpine.Repository != "gofunky/app" {
+		t.Errorf("BuildLibrary() modified the repository of its source to %q", alpine.Repository)
+	}
+}
+
+func Test_libraryDirectory(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		baseDir string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Git Top Level",
+			file: "../../test/builds/worker/Dockerfile.worker",
+			want: "test/builds/worker",
+		},
+		{
+			name:    "Base Directory",
+			file:    "../../test/builds/worker/Dockerfile.worker",
+			baseDir: "../../test",
+			want:    "builds/worker",
+		},
+		{
+			name:    "Same Directory",
+			file:    "../../test/builds/worker/Dockerfile.worker",
+			baseDir: "../../test/builds/worker",
+			want:    ".",
+		},
+		{
+			name:    "Outside Base Directory",
+			file:    "../../test/builds/worker/Dockerfile.worker",
+			baseDir: "../../test/builds/app",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := libraryDirectory(tt.file, tt.baseDir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("libraryDirectory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("libraryDirectory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTuplip_FromLibrary(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  LibraryOptions
+		wantTags [][]interface{}
+		wantErr  bool
+	}{
+		{
+			name: "All Entries",
+			wantTags: [][]interface{}{
+				{"app:1.2.0-alpine3.19", "app:1.2-alpine3.19", "app:1-alpine3.19", "app:alpine3.19", "app:alpine"},
+				{"app:1.2.0-bookworm", "app:1.2-bookworm", "app:1-bookworm", "app:bookworm",
+					"app:1.2.0", "app:1.2", "app:1", "app:latest"},
+			},
+		},
+		{
+			name:     "Selected Directory",
+			options:  LibraryOptions{Directories: []string{"1.2/alpine/"}},
+			wantTags: [][]interface{}{{"app:1.2.0-alpine3.19", "app:1.2-alpine3.19", "app:1-alpine3.19", "app:alpine3.19", "app:alpine"}},
+		},
+		{
+			name:    "Missing Directory",
+			options: LibraryOptions{Directories: []string{"1.2/windows"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, err := new(Tuplip).FromLibrary("../../test/library/app", tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromLibrary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(sources) != len(tt.wantTags) {
+				t.Fatalf("Tuplip.FromLibrary() = %d sources, want %d", len(sources), len(tt.wantTags))
+			}
+			for i, src := range sources {
+				src.stream = src.Build(false)
+				if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantTags[i]...)) {
+					t.Errorf("Tuplip.FromLibrary()[%d] = %v, want %v", i, got, tt.wantTags[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package tupliplib
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestSchema is the JSON schema of the tuplip manifest.
+//
+//go:embed manifest.schema.json
+var ManifestSchema string
+
+// ManifestFiles are the file names that are looked up in the given order if no manifest file is specified.
+var ManifestFiles = []string{"tuplip.yaml", "tuplip.yml", "tuplip.json"}
+
+// repositoryPattern matches a Docker repository optionally prefixed by a registry host.
+var repositoryPattern = regexp.MustCompile(
+	`^([a-zA-Z0-9.-]+(:[0-9]+)?/)?[a-z0-9]+([._-]{1,2}[a-z0-9]+)*(/[a-z0-9]+([._-]{1,2}[a-z0-9]+)*)*$`,
+)
+
+// Manifest is the declarative description of one or more images and their tag vectors.
+type Manifest struct {
+	// Images are the images that are described by the manifest.
+	Images []ManifestImage `json:"images" yaml:"images"`
+}
+
+// ManifestImage describes a single image with its tag vectors, target repositories, and tag policy.
+type ManifestImage struct {
+	// Name identifies the image in the manifest.
+	Name string `json:"name" yaml:"name"`
+	// Repositories are the target repositories that receive the tags of the image.
+	Repositories []string `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	// Vectors are the tag vectors of the image.
+	Vectors []VectorSpec `json:"vectors" yaml:"vectors"`
+	// Policy extends the tag generation options for this image.
+	Policy ManifestPolicy `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+// ManifestPolicy defines the tag generation options of a manifest image.
+// Enabled options extend the options of the Tuplip instance.
+type ManifestPolicy struct {
+	// ExcludeMajor excludes the major versions from the considered version variants.
+	ExcludeMajor bool `json:"exclude-major,omitempty" yaml:"exclude-major,omitempty"`
+	// ExcludeMinor excludes the minor versions from the considered version variants.
+	ExcludeMinor bool `json:"exclude-minor,omitempty" yaml:"exclude-minor,omitempty"`
+	// ExcludeBase excludes the base alias without version suffix from the considered version variants.
+	ExcludeBase bool `json:"exclude-base,omitempty" yaml:"exclude-base,omitempty"`
+	// AddLatest adds an additional 'latest' tag to the result set.
+	AddLatest bool `json:"add-latest,omitempty" yaml:"add-latest,omitempty"`
+	// ExclusiveLatest makes the `latest` tag vector version an exclusive tag if given.
+	ExclusiveLatest bool `json:"exclusive-latest,omitempty" yaml:"exclusive-latest,omitempty"`
+	// Filter excludes all tags without the given set of tag vectors from the output set.
+	Filter []string `json:"filter,omitempty" yaml:"filter,omitempty"`
+}
+
+// ManifestError is a validation error of a manifest field.
+type ManifestError struct {
+	// Line is the line in the manifest file that contains the invalid field or its parent.
+	Line int
+	// Field is the path of the invalid field (e.g., `images[0].vectors[1].version`).
+	Field string
+	// Err is the validation error of the field.
+	Err error
+}
+
+// Error implements error.
+func (e *ManifestError) Error() string {
+	return fmt.Sprintf("line %d: %s: %v", e.Line, e.Field, e.Err)
+}
+
+// Unwrap returns the validation error of the field.
+func (e *ManifestError) Unwrap() error {
+	return e.Err
+}
+
+// ManifestErrors are all validation errors of a manifest ordered by their line and field.
+type ManifestErrors []*ManifestError
+
+// Error implements error.
+func (es ManifestErrors) Error() string {
+	messages := make([]string, len(es))
+	for i, e := range es {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate implements validation.Validatable.
+func (m Manifest) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Images, validation.Required, validation.By(uniqueImageNames)),
+	)
+}
+
+// Validate implements validation.Validatable.
+func (i ManifestImage) Validate() error {
+	return validation.ValidateStruct(&i,
+		validation.Field(&i.Name, validation.Required),
+		validation.Field(&i.Repositories, validation.Each(validation.Match(repositoryPattern))),
+		validation.Field(&i.Vectors, validation.Required, validation.By(singleRootVector)),
+	)
+}
+
+// uniqueImageNames ensures that no image name is used twice.
+func uniqueImageNames(value interface{}) error {
+	names := make(map[string]bool)
+	for _, image := range value.([]ManifestImage) {
+		if names[image.Name] {
+			return fmt.Errorf("the image name '%s' is not unique", image.Name)
+		}
+		names[image.Name] = true
+	}
+	return nil
+}
+
+// singleRootVector ensures that an image contains at most one root tag vector.
+func singleRootVector(value interface{}) error {
+	var roots int
+	for _, vector := range value.([]VectorSpec) {
+		if vector.kind() == RootVector {
+			roots++
+		}
+	}
+	if roots > 1 {
+		return errors.New("must not contain more than one root vector")
+	}
+	return nil
+}
+
+// ReadManifest parses and validates a tuplip manifest in YAML or JSON format.
+// Validation errors are returned as ManifestErrors.
+func ReadManifest(src io.Reader) (manifest *Manifest, err error) {
+	content, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	manifest = new(Manifest)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(manifest); err != nil {
+		if err == io.EOF {
+			err = errors.New("the given manifest is empty")
+		}
+		return nil, err
+	}
+	if err = manifest.Validate(); err != nil {
+		var errs ManifestErrors
+		collectManifestErrors(err, nil, rootNode(&document), &errs)
+		sort.Slice(errs, func(i, j int) bool {
+			if errs[i].Line == errs[j].Line {
+				return errs[i].Field < errs[j].Field
+			}
+			return errs[i].Line < errs[j].Line
+		})
+		return nil, errs
+	}
+	return manifest, nil
+}
+
+// rootNode returns the content node of the given YAML document.
+func rootNode(document *yaml.Node) *yaml.Node {
+	if document.Kind == yaml.DocumentNode && len(document.Content) > 0 {
+		return document.Content[0]
+	}
+	return document
+}
+
+// childNode finds the YAML node for the given mapping key or sequence index.
+// It returns nil if the node has no such child.
+func childNode(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(node.Content) {
+			return node.Content[index]
+		}
+	}
+	return nil
+}
+
+// collectManifestErrors flattens the given validation errors and locates their lines in the given YAML node.
+// Errors of missing fields are located at their parent node.
+func collectManifestErrors(err error, path []string, node *yaml.Node, errs *ManifestErrors) {
+	if fieldErrs, ok := err.(validation.Errors); ok {
+		for key, fieldErr := range fieldErrs {
+			child := childNode(node, key)
+			if child == nil {
+				child = node
+			}
+			collectManifestErrors(fieldErr, append(path[:len(path):len(path)], key), child, errs)
+		}
+		return
+	}
+	var line int
+	if node != nil {
+		line = node.Line
+	}
+	*errs = append(*errs, &ManifestError{Line: line, Field: fieldPath(path), Err: err})
+}
+
+// fieldPath formats the given path of keys and indices (e.g., `images[0].name`).
+func fieldPath(path []string) string {
+	var builder strings.Builder
+	for _, key := range path {
+		if _, err := strconv.Atoi(key); err == nil {
+			builder.WriteString("[" + key + "]")
+		} else {
+			if builder.Len() > 0 {
+				builder.WriteString(".")
+			}
+			builder.WriteString(key)
+		}
+	}
+	return builder.String()
+}
+
+// findManifest returns the given manifest file or the first of the ManifestFiles that exists.
+func findManifest(src string) (string, error) {
+	if src != "" {
+		return src, nil
+	}
+	for _, name := range ManifestFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("none of the manifest files %v could be found", ManifestFiles)
+}
+
+// FromManifest builds a tuplip source for each image and target repository from a tuplip manifest.
+// If src is empty, the first existing file of the ManifestFiles is used.
+// If images is non-empty, only the images with the given names are considered.
+func (t *Tuplip) FromManifest(src string, images []string) (sources []*TuplipSource, err error) {
+	if src, err = findManifest(src); err != nil {
+		return nil, err
+	}
+	logger.InfoWith("queueing read from manifest").
+		String("file", src).
+		Write()
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(absSrc)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	manifest, err := ReadManifest(file)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool)
+	for _, name := range images {
+		selected[name] = false
+	}
+	for _, image := range manifest.Images {
+		if _, ok := selected[image.Name]; len(images) > 0 && !ok {
+			continue
+		}
+		selected[image.Name] = true
+		sources = append(sources, t.fromManifestImage(image)...)
+	}
+	for name, found := range selected {
+		if !found {
+			return nil, fmt.Errorf("the image '%s' could not be found in the manifest", name)
+		}
+	}
+	return sources, nil
+}
+
+// fromManifestImage builds a tuplip source for each target repository of the given manifest image.
+func (t *Tuplip) fromManifestImage(image ManifestImage) (sources []*TuplipSource) {
+	imageTuplip := *t
+	imageTuplip.ExcludeMajor = t.ExcludeMajor || image.Policy.ExcludeMajor
+	imageTuplip.ExcludeMinor = t.ExcludeMinor || image.Policy.ExcludeMinor
+	imageTuplip.ExcludeBase = t.ExcludeBase || image.Policy.ExcludeBase
+	imageTuplip.AddLatest = t.AddLatest || image.Policy.AddLatest
+	imageTuplip.ExclusiveLatest = t.ExclusiveLatest || image.Policy.ExclusiveLatest
+	imageTuplip.Filter = append(append([]string{}, t.Filter...), image.Policy.Filter...)
+	vectors := make([]string, len(image.Vectors))
+	options := make(map[string]VectorOptions)
+	for i, vector := range image.Vectors {
+		vectors[i] = vector.String()
+		options[vector.alias()] = vector.Options
+	}
+	repositories := image.Repositories
+	if len(repositories) == 0 {
+		repositories = []string{""}
+	}
+	for _, repository := range repositories {
+		logger.InfoWith("queueing manifest image").
+			String("image", image.Name).
+			String("repository", repository).
+			Write()
+		source := imageTuplip.FromSlice(vectors)
+		source.Repository = repository
+		source.options = options
+		source.image = image.Name
+		sources = append(sources, source)
+	}
+	return
+}
@@ -0,0 +1,217 @@
+package tupliplib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		manifest   string
+		wantImages []string
+		wantErrs   []string
+		wantErr    bool
+	}{
+		{
+			name:    "Empty",
+			wantErr: true,
+		},
+		{
+			name:     "No Images",
+			manifest: "images: []",
+			wantErrs: []string{"line 1: images: cannot be blank"},
+		},
+		{
+			name:     "Unknown Field",
+			manifest: "images:\n  - name: foo\n    vectors: [{alias: foo}]\n    unknown: true\n",
+			wantErr:  true,
+		},
+		{
+			name: "YAML",
+			manifest: `images:
+  - name: git
+    repositories: [gofunky/git, ghcr.io/gofunky/git]
+    vectors:
+      - version: 1.0.0
+      - alias: alpine
+        version: "3.8"
+      - alias: foo
+  - name: docker
+    vectors:
+      - kind: root
+        alias: _
+        version: latest
+`,
+			wantImages: []string{"git", "docker"},
+		},
+		{
+			name:       "JSON",
+			manifest:   `{"images": [{"name": "git", "vectors": [{"alias": "foo"}, {"version": "1.2"}]}]}`,
+			wantImages: []string{"git"},
+		},
+		{
+			name: "Invalid Fields",
+			manifest: `images:
+  - name: git
+    repositories: [Invalid/Repo]
+    vectors:
+      - alias: al-pine
+        version: "3.8"
+      - kind: alias
+        alias: foo
+        version: "1.0"
+      - kind: dependency
+        alias: goo
+  - vectors:
+      - version: "1"
+      - version: "2"
+`,
+			wantErrs: []string{
+				"line 3: images[0].repositories[0]: must be in a valid format",
+				"line 5: images[0].vectors[0].alias: must be in a valid format",
+				"line 9: images[0].vectors[1].version: must be empty for alias vectors",
+				"line 10: images[0].vectors[2].version: cannot be blank",
+				"line 12: images[1].name: cannot be blank",
+				"line 13: images[1].vectors: must not contain more than one root vector",
+			},
+		},
+		{
+			name:     "Duplicate Image Names",
+			manifest: "images:\n  - name: foo\n    vectors: [{alias: foo}]\n  - name: foo\n    vectors: [{alias: goo}]\n",
+			wantErrs: []string{"line 2: images: the image name 'foo' is not unique"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := ReadManifest(strings.NewReader(tt.manifest))
+			if tt.wantErrs != nil {
+				manifestErrs, ok := err.(ManifestErrors)
+				if !ok {
+					t.Fatalf("ReadManifest() error = %v, want ManifestErrors", err)
+				}
+				gotErrs := make([]string, len(manifestErrs))
+				for i, e := range manifestErrs {
+					gotErrs[i] = e.Error()
+				}
+				if !cmp.Equal(gotErrs, tt.wantErrs) {
+					t.Errorf("ReadManifest() errors = %v, want %v", gotErrs, tt.wantErrs)
+				}
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var gotImages []string
+			for _, image := range manifest.Images {
+				gotImages = append(gotImages, image.Name)
+			}
+			if !cmp.Equal(gotImages, tt.wantImages) {
+				t.Errorf("ReadManifest() images = %v, want %v", gotImages, tt.wantImages)
+			}
+		})
+	}
+}
+
+func TestVectorSpec_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		vector VectorSpec
+		want   string
+	}{
+		{
+			name:   "Alias",
+			vector: VectorSpec{Alias: "foo"},
+			want:   "foo",
+		},
+		{
+			name:   "Dependency",
+			vector: VectorSpec{Alias: "alpine", Version: "3.8"},
+			want:   "alpine:3.8",
+		},
+		{
+			name:   "Implicit Root",
+			vector: VectorSpec{Version: "1.0.0"},
+			want:   "_:1.0.0",
+		},
+		{
+			name:   "Explicit Root",
+			vector: VectorSpec{Kind: RootVector, Alias: "_", Version: "1.0.0"},
+			want:   "_:1.0.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.vector.String(); got != tt.want {
+				t.Errorf("VectorSpec.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTuplip_FromManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Tuplip
+		images  []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "Single Image",
+			images: []string{"docker"},
+			want:   []string{"goo", "18", "18.9", "18-goo", "18.9-goo"},
+		},
+		{
+			name:   "Policy And Vector Options",
+			images: []string{"git"},
+			want: []string{
+				"gofunky/git:foo", "gofunky/git:2", "gofunky/git:2.4.1", "gofunky/git:2-foo", "gofunky/git:2.4.1-foo",
+				"gofunky/git:alpine", "gofunky/git:2-alpine", "gofunky/git:2.4.1-alpine",
+				"gofunky/git:alpine-foo", "gofunky/git:2-alpine-foo", "gofunky/git:2.4.1-alpine-foo",
+				"gofunky/ignore:foo", "gofunky/ignore:2", "gofunky/ignore:2.4.1", "gofunky/ignore:2-foo",
+				"gofunky/ignore:2.4.1-foo", "gofunky/ignore:alpine", "gofunky/ignore:2-alpine",
+				"gofunky/ignore:2.4.1-alpine", "gofunky/ignore:alpine-foo", "gofunky/ignore:2-alpine-foo",
+				"gofunky/ignore:2.4.1-alpine-foo",
+			},
+		},
+		{
+			name:   "Policy Extends Options",
+			t:      Tuplip{ExcludeMajor: true},
+			images: []string{"docker"},
+			want:   []string{"goo", "18.9", "18.9-goo"},
+		},
+		{
+			name:    "Unknown Image",
+			images:  []string{"unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.t.Simulate = true
+			sources, err := tt.t.FromManifest("../../test/tuplip.yaml", tt.images)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			gotOutput := mapset.NewSet()
+			for _, src := range sources {
+				gotOutput = gotOutput.Union(collectStream(t, src.Build(false)))
+			}
+			wantSet := mapset.NewSet()
+			for _, w := range tt.want {
+				wantSet.Add(w)
+			}
+			if !gotOutput.Equal(wantSet) {
+				t.Errorf("Tuplip.Build() = %v, want %v, difference %v",
+					gotOutput, wantSet, gotOutput.Difference(wantSet))
+			}
+		})
+	}
+}
@@ -108,6 +108,22 @@ func (t Tuplip) splitVersion(requireSemver bool) func(inputTag string) (result m
 	}
 }
 
+// withOptions returns a copy of the tuplip options that is extended by the given vector options.
+func (t Tuplip) withOptions(options VectorOptions) Tuplip {
+	t.ExcludeMajor = t.ExcludeMajor || options.ExcludeMajor
+	t.ExcludeMinor = t.ExcludeMinor || options.ExcludeMinor
+	t.ExcludeBase = t.ExcludeBase || options.ExcludeBase
+	return t
+}
+
+// splitVersion works like Tuplip.splitVersion but considers the vector options of the source for each input tag.
+func (s *TuplipSource) splitVersion(requireSemver bool) func(inputTag string) (result mapset.Set, err error) {
+	return func(inputTag string) (result mapset.Set, err error) {
+		alias := strings.TrimSpace(strings.SplitN(inputTag, VersionSeparator, 2)[0])
+		return s.tuplip.withOptions(s.options[alias]).splitVersion(requireSemver)(inputTag)
+	}
+}
+
 // splitBySeparator generates a function separates the input string by the given character and trims superfluous spaces.
 func (t Tuplip) splitBySeparator(sep string) func(input string) []string {
 	if sep == "" {
@@ -215,12 +231,7 @@ func (s *TuplipSource) getTags() (tagMap map[string]mapset.Set, err error) {
 		return nil, errors.New("no Docker tags could be found on the given remote")
 	}
 	for _, tag := range tags {
-		tagVectors := strings.Split(tag, DockerTagSeparator)
-		vectorSet := mapset.NewSet()
-		for _, v := range tagVectors {
-			vectorSet.Add(v)
-		}
-		tagMap[tag] = vectorSet
+		tagMap[tag] = s.tuplip.Conventions.tagSet(s.Repository, tag)
 	}
 	return
 }
@@ -0,0 +1,148 @@
+package tupliplib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofunky/automi/collectors"
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+// MergeOptions contain the parameters for merging multiple tuplip sources.
+type MergeOptions struct {
+	// FailOnConflict fails the merge if two sources provide the same alias with different versions.
+	FailOnConflict bool `help:"fail if two merged sources provide the same tag vector alias with different versions"`
+}
+
+// MergeConflict is a tag vector alias that two merged sources provide with different versions.
+type MergeConflict struct {
+	// Alias is the alias of the conflicting tag vectors. Root tag vectors have the alias `_`.
+	Alias string
+	// Version is the version of the source with the higher precedence.
+	Version string
+	// Ignored is the version of the source with the lower precedence.
+	Ignored string
+}
+
+// Error implements error.
+func (c MergeConflict) Error() string {
+	return fmt.Sprintf("the tag vector '%s' has the conflicting versions '%s' and '%s'", c.Alias, c.Version,
+		c.Ignored)
+}
+
+// MergeConflicts are all conflicts of a merge in the order of their occurrence.
+type MergeConflicts []MergeConflict
+
+// Error implements error.
+func (cs MergeConflicts) Error() string {
+	messages := make([]string, len(cs))
+	for i, c := range cs {
+		messages[i] = c.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Merge combines the tag vectors of the given sources into a single source. The sources are read immediately.
+// The sources are given in the order of their precedence. If a source provides a tag vector alias that a preceding
+// source already provides, its vectors with that alias are ignored. Different versions are reported as conflicts,
+// which fail the merge if the options demand it.
+// The tuplip options of the sources are combined. The repository, Dockerfile, digests, and vector options of a source
+// take precedence over the ones of the subsequent sources. The merged source is straight if any source is straight.
+func Merge(options MergeOptions, sources ...*TuplipSource) (source *TuplipSource, err error) {
+	if len(sources) == 0 {
+		return nil, errors.New("at least one source is required to merge")
+	}
+	logger.InfoWith("merging sources").
+		Int("sources", len(sources)).
+		Write()
+	tuplip := *sources[0].tuplip
+	source = &TuplipSource{
+		tuplip:  &tuplip,
+		options: make(map[string]VectorOptions),
+		Digests: make(map[string]string),
+	}
+	for i := len(sources) - 1; i >= 0; i-- {
+		src := sources[i]
+		tuplip = tuplip.combine(*src.tuplip)
+		if src.Repository != "" {
+			source.Repository = src.Repository
+		}
+		if src.file != "" {
+			source.file = src.file
+		}
+		if src.image != "" {
+			source.image = src.image
+		}
+		source.straight = source.straight || src.straight
+		for alias, digest := range src.Digests {
+			source.Digests[alias] = digest
+		}
+		for alias, options := range src.options {
+			source.options[alias] = options
+		}
+	}
+	var vectors []string
+	var conflicts MergeConflicts
+	owners := make(map[string]int)
+	versions := make(map[string][]string)
+	for i, src := range sources {
+		collector := collectors.Slice()
+		src.stream.Into(collector)
+		if err = <-src.stream.Open(); err != nil {
+			return nil, err
+		}
+		for _, item := range collector.Get() {
+			vector := ParseVector(item.(string))
+			alias := vector.alias()
+			if owner, ok := owners[alias]; !ok || owner == i {
+				owners[alias] = i
+				versions[alias] = append(versions[alias], vector.Version)
+				vectors = append(vectors, item.(string))
+			} else if !containsString(versions[alias], vector.Version) {
+				conflict := MergeConflict{Alias: alias, Version: versions[alias][0], Ignored: vector.Version}
+				logger.WarnWith("ignoring conflicting tag vector").
+					String("alias", alias).
+					String("version", conflict.Version).
+					String("ignored version", conflict.Ignored).
+					Write()
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+	if options.FailOnConflict && len(conflicts) > 0 {
+		return nil, conflicts
+	}
+	source.stream = stream.New(emitters.Slice(vectors))
+	return source, nil
+}
+
+// combine enables the options of the given tuplip in a copy of this tuplip and adds its filter vectors.
+// It never disables an option. The conventions of this tuplip are kept.
+func (t Tuplip) combine(other Tuplip) Tuplip {
+	t.ExcludeMajor = t.ExcludeMajor || other.ExcludeMajor
+	t.ExcludeMinor = t.ExcludeMinor || other.ExcludeMinor
+	t.ExcludeBase = t.ExcludeBase || other.ExcludeBase
+	t.Simulate = t.Simulate || other.Simulate
+	t.AddLatest = t.AddLatest || other.AddLatest
+	t.ExclusiveLatest = t.ExclusiveLatest || other.ExclusiveLatest
+	filter := append([]string{}, t.Filter...)
+	for _, vector := range other.Filter {
+		if !containsString(filter, vector) {
+			filter = append(filter, vector)
+		}
+	}
+	t.Filter = filter
+	return t
+}
+
+// containsString marks if the given values contain the given value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package tupliplib
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	tuplip := new(Tuplip)
+	file, err := tuplip.FromFile("../../test/Directives.Dockerfile", FileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Repository = "gofunky/service"
+	goMod, err := tuplip.FromGoMod("../../test/service.go.mod", GoModOptions{Modules: []string{"github.com/gofunky/*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	goMod.Repository = "gofunky/other"
+	other := tuplip.FromSlice([]string{"foo"})
+	src, err := Merge(MergeOptions{}, other, file, goMod)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if src.Repository != "gofunky/service" {
+		t.Errorf("Merge() repository = %v, want %v", src.Repository, "gofunky/service")
+	}
+	if !src.tuplip.AddLatest || !cmp.Equal(src.tuplip.Filter, []string{"go"}) {
+		t.Errorf("Merge() did not combine the tuplip options of the sources")
+	}
+	wantOptions := map[string]VectorOptions{
+		"go":     {ExcludeMajor: true},
+		"alpine": {ExcludeMinor: true, ExcludeBase: true},
+	}
+	if !cmp.Equal(src.options, wantOptions) {
+		t.Errorf("Merge() options = %v, want %v", src.options, wantOptions)
+	}
+	want := mapset.NewSet("go:1.22.3", "alpine:3.19", "_:1.2.0", "golang:1.23.1", "automi:0.2.0", "foo")
+	if got := collectVectors(t, src); !got.Equal(want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+	if _, err = Merge(MergeOptions{}); err == nil {
+		t.Errorf("Merge() without sources did not fail")
+	}
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	tests := []struct {
+		name          string
+		sources       [][]string
+		options       MergeOptions
+		wantVectors   []interface{}
+		wantConflicts MergeConflicts
+	}{
+		{
+			name:        "Precedence",
+			sources:     [][]string{{"_:2.0", "feature"}, {"_:1.0", "alpine:3.19"}, {"alpine:3.18", "feature"}},
+			wantVectors: []interface{}{"_:2.0", "feature", "alpine:3.19"},
+		},
+		{
+			name:        "Multiple Versions In One Source",
+			sources:     [][]string{{"go:1.22", "go:1.21"}, {"go:1.21", "node:20"}},
+			wantVectors: []interface{}{"go:1.22", "go:1.21", "node:20"},
+		},
+		{
+			name:    "Fail On Conflict",
+			sources: [][]string{{"_:2.0", "feature"}, {"_:1.0", "feature:1"}, {"_:2.0"}},
+			options: MergeOptions{FailOnConflict: true},
+			wantConflicts: MergeConflicts{
+				{Alias: "_", Version: "2.0", Ignored: "1.0"},
+				{Alias: "feature", Version: "", Ignored: "1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tuplip := new(Tuplip)
+			var sources []*TuplipSource
+			for _, vectors := range tt.sources {
+				sources = append(sources, tuplip.FromSlice(vectors))
+			}
+			src, err := Merge(tt.options, sources...)
+			var conflicts MergeConflicts
+			if errors.As(err, &conflicts) {
+				if !cmp.Equal(conflicts, tt.wantConflicts) {
+					t.Errorf("Merge() conflicts = %v, want %v", conflicts, tt.wantConflicts)
+				}
+				return
+			} else if err != nil || tt.wantConflicts != nil {
+				t.Fatalf("Merge() error = %v, want conflicts %v", err, tt.wantConflicts)
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Merge() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
+
+func TestParseVector(t *testing.T) {
+	tests := []struct {
+		text     string
+		want     VectorSpec
+		wantKind VectorKind
+	}{
+		{text: "alpine:3.8", want: VectorSpec{Alias: "alpine", Version: "3.8"}, wantKind: DependencyVector},
+		{text: "_:1.0.0", want: VectorSpec{Alias: "_", Version: "1.0.0"}, wantKind: RootVector},
+		{text: "feature", want: VectorSpec{Alias: "feature"}, wantKind: AliasVector},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := ParseVector(tt.text)
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("ParseVector() = %v, want %v", got, tt.want)
+			}
+			if got.kind() != tt.wantKind {
+				t.Errorf("ParseVector() kind = %v, want %v", got.kind(), tt.wantKind)
+			}
+			if got.String() != tt.text {
+				t.Errorf("ParseVector() = %v, want %v", got.String(), tt.text)
+			}
+		})
+	}
+}
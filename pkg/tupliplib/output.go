@@ -0,0 +1,164 @@
+package tupliplib
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat depicts the format of the Docker tags that are written to a writer.
+type OutputFormat string
+
+const (
+	// TextOutput writes one Docker tag per line.
+	TextOutput OutputFormat = "text"
+	// JSONOutput writes a JSON array of TagRecords.
+	JSONOutput OutputFormat = "json"
+	// YAMLOutput writes a YAML sequence of TagRecords.
+	YAMLOutput OutputFormat = "yaml"
+	// CSVOutput writes one TagRecord per row after a header row. The vectors are separated by spaces.
+	CSVOutput OutputFormat = "csv"
+	// NULOutput terminates each Docker tag by a NUL character (e.g., for `xargs -0`).
+	NULOutput OutputFormat = "nul"
+)
+
+// TagRecord describes a built Docker tag for the structured output formats.
+type TagRecord struct {
+	// Repository is the repository of the tag, or empty if the tag has no repository.
+	Repository string `json:"repository" yaml:"repository"`
+	// Tag is the Docker tag without repository.
+	Tag string `json:"tag" yaml:"tag"`
+	// Vectors are the tag vectors that the tag consists of.
+	Vectors []string `json:"vectors" yaml:"vectors"`
+	// Specificity is the number of tag vectors and version components of the tag.
+	Specificity int `json:"specificity" yaml:"specificity"`
+	// Source is the source that the tag was built from if the tags of multiple sources are written.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// TagGroup contains the Docker tags that were built from the same source.
+type TagGroup struct {
+	// Source names the source of the tags (e.g., its Dockerfile).
+	Source string
+	// Tags are the built Docker tags.
+	Tags []string
+}
+
+// NewTagRecord describes the given Docker tag, which may be prefixed by a repository.
+func NewTagRecord(tag string) TagRecord {
+	record := TagRecord{Tag: tag, Specificity: TagSpecificity(tag)}
+	if index := strings.LastIndex(tag, VersionSeparator); index > strings.LastIndex(tag, RepositorySeparator) {
+		record.Repository, record.Tag = tag[:index], tag[index+1:]
+	}
+	for _, vector := range strings.Split(record.Tag, DockerTagSeparator) {
+		if vector != "" {
+			record.Vectors = append(record.Vectors, vector)
+		}
+	}
+	return record
+}
+
+// WriteTags writes the given Docker tags in the given format. Duplicate tags are omitted and the tags are sorted
+// by their specificity, the most specific tags first, and lexically otherwise, so that the output is deterministic.
+func WriteTags(writer io.Writer, tags []string, format OutputFormat) error {
+	return WriteTagGroups(writer, []TagGroup{{Tags: tags}}, format)
+}
+
+// WriteTagGroups writes the Docker tags of the given groups in the given format, one group after another.
+// The tags of each group are deduplicated and sorted like the ones of WriteTags, but the tags of different groups
+// are not mixed. If multiple groups are given, the text output precedes each group by a comment line with its source,
+// and the records of the structured formats contain their source.
+func WriteTagGroups(writer io.Writer, groups []TagGroup, format OutputFormat) (err error) {
+	multiple := len(groups) > 1
+	var records []TagRecord
+	var sortedGroups [][]string
+	for _, group := range groups {
+		sorted := sortTags(group.Tags)
+		sortedGroups = append(sortedGroups, sorted)
+		for _, tag := range sorted {
+			record := NewTagRecord(tag)
+			if multiple {
+				record.Source = group.Source
+			}
+			records = append(records, record)
+		}
+	}
+	if records == nil {
+		records = []TagRecord{}
+	}
+	switch format {
+	case TextOutput, "":
+		for i, sorted := range sortedGroups {
+			if multiple {
+				if _, err = fmt.Fprintf(writer, "# %s\n", groups[i].Source); err != nil {
+					return err
+				}
+			}
+			for _, tag := range sorted {
+				if _, err = fmt.Fprintln(writer, tag); err != nil {
+					return err
+				}
+			}
+		}
+	case NULOutput:
+		for _, sorted := range sortedGroups {
+			for _, tag := range sorted {
+				if _, err = fmt.Fprint(writer, tag+"\x00"); err != nil {
+					return err
+				}
+			}
+		}
+	case JSONOutput:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case YAMLOutput:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err = encoder.Encode(records); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case CSVOutput:
+		csvWriter := csv.NewWriter(writer)
+		header := []string{"repository", "tag", "vectors", "specificity"}
+		if multiple {
+			header = append(header, "source")
+		}
+		if err = csvWriter.Write(header); err != nil {
+			return err
+		}
+		for _, record := range records {
+			row := []string{record.Repository, record.Tag, strings.Join(record.Vectors, Space),
+				strconv.Itoa(record.Specificity)}
+			if multiple {
+				row = append(row, record.Source)
+			}
+			if err = csvWriter.Write(row); err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	default:
+		return fmt.Errorf("the output format '%s' is not supported", format)
+	}
+	return nil
+}
+
+// sortTags returns the given Docker tags without duplicates, sorted by their specificity and lexically otherwise.
+func sortTags(tags []string) (sorted []string) {
+	for _, tag := range tags {
+		if !containsString(sorted, tag) {
+			sorted = append(sorted, tag)
+		}
+	}
+	sort.Sort(SortedTags(sorted))
+	return sorted
+}
@@ -0,0 +1,204 @@
+package tupliplib
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestNewTagRecord(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want TagRecord
+	}{
+		{"1.2-alpine3.8", TagRecord{"", "1.2-alpine3.8", []string{"1.2", "alpine3.8"}, 4, ""}},
+		{"gofunky/app:latest", TagRecord{"gofunky/app", "latest", []string{"latest"}, 1, ""}},
+		{"localhost:5000/app:1-go", TagRecord{"localhost:5000/app", "1-go", []string{"1", "go"}, 2, ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := NewTagRecord(tt.tag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewTagRecord() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTags(t *testing.T) {
+	tags := []string{"gofunky/app:alpine", "gofunky/app:1.2-alpine", "gofunky/app:1", "gofunky/app:alpine"}
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Text",
+			format: TextOutput,
+			want:   "gofunky/app:1.2-alpine\ngofunky/app:1\ngofunky/app:alpine\n",
+		},
+		{
+			name:   "NUL",
+			format: NULOutput,
+			want:   "gofunky/app:1.2-alpine\x00gofunky/app:1\x00gofunky/app:alpine\x00",
+		},
+		{
+			name:   "JSON",
+			format: JSONOutput,
+			want: `[
+  {
+    "repository": "gofunky/app",
+    "tag": "1.2-alpine",
+    "vectors": [
+      "1.2",
+      "alpine"
+    ],
+    "specificity": 3
+  },
+  {
+    "repository": "gofunky/app",
+    "tag": "1",
+    "vectors": [
+      "1"
+    ],
+    "specificity": 1
+  },
+  {
+    "repository": "gofunky/app",
+    "tag": "alpine",
+    "vectors": [
+      "alpine"
+    ],
+    "specificity": 1
+  }
+]
+`,
+		},
+		{
+			name:   "YAML",
+			format: YAMLOutput,
+			want: `- repository: gofunky/app
+  tag: 1.2-alpine
+  vectors:
+    - "1.2"
+    - alpine
+  specificity: 3
+- repository: gofunky/app
+  tag: "1"
+  vectors:
+    - "1"
+  specificity: 1
+- repository: gofunky/app
+  tag: alpine
+  vectors:
+    - alpine
+  specificity: 1
+`,
+		},
+		{
+			name:   "CSV",
+			format: CSVOutput,
+			want: "repository,tag,vectors,specificity\n" +
+				"gofunky/app,1.2-alpine,1.2 alpine,3\n" +
+				"gofunky/app,1,1,1\n" +
+				"gofunky/app,alpine,alpine,1\n",
+		},
+		{
+			name:    "Unknown Format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			err := WriteTags(&buffer, tags, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := buffer.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("WriteTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTagGroups(t *testing.T) {
+	groups := []TagGroup{
+		{Source: "node/Dockerfile", Tags: []string{"latest", "1.2", "latest"}},
+		{Source: "golang/Dockerfile", Tags: []string{"latest", "go"}},
+	}
+	tests := []struct {
+		name   string
+		groups []TagGroup
+		format OutputFormat
+		want   string
+	}{
+		{
+			name:   "Text",
+			groups: groups,
+			format: TextOutput,
+			want:   "# node/Dockerfile\n1.2\nlatest\n# golang/Dockerfile\ngo\nlatest\n",
+		},
+		{
+			name:   "NUL",
+			groups: groups,
+			format: NULOutput,
+			want:   "1.2\x00latest\x00go\x00latest\x00",
+		},
+		{
+			name:   "CSV",
+			groups: groups,
+			format: CSVOutput,
+			want: "repository,tag,vectors,specificity,source\n" +
+				",1.2,1.2,2,node/Dockerfile\n" +
+				",latest,latest,1,node/Dockerfile\n" +
+				",go,go,1,golang/Dockerfile\n" +
+				",latest,latest,1,golang/Dockerfile\n",
+		},
+		{
+			name:   "YAML",
+			groups: groups[1:],
+			format: YAMLOutput,
+			want: `- repository: ""
+  tag: go
+  vectors:
+    - go
+  specificity: 1
+- repository: ""
+  tag: latest
+  vectors:
+    - latest
+  specificity: 1
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := WriteTagGroups(&buffer, tt.groups, tt.format); err != nil {
+				t.Fatalf("WriteTagGroups() error = %v", err)
+			}
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("WriteTagGroups() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTags_Empty(t *testing.T) {
+	for format, want := range map[OutputFormat]string{
+		TextOutput: "",
+		JSONOutput: "[]\n",
+		YAMLOutput: "[]\n",
+		CSVOutput:  "repository,tag,vectors,specificity\n",
+	} {
+		var buffer bytes.Buffer
+		if err := WriteTags(&buffer, nil, format); err != nil {
+			t.Fatalf("WriteTags() error = %v", err)
+		}
+		if got := buffer.String(); got != want {
+			t.Errorf("WriteTags(%s) = %q, want %q", format, got, want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package tupliplib
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+// ToolVersionsFile is the asdf file that pins the versions of multiple tools, one tool per line.
+const ToolVersionsFile = ".tool-versions"
+
+// PinFiles map the files that pin the version of a single tool to the tool name.
+// The tool names follow the asdf plugin names.
+var PinFiles = map[string]string{
+	".nvmrc":          "nodejs",
+	".python-version": "python",
+	".java-version":   "java",
+}
+
+// ToolAliases map the tool names to the vector aliases that differ from the normalized tool name.
+var ToolAliases = map[string]string{
+	"nodejs": "node",
+}
+
+// PinOptions contain the parameters for reading tag vectors from runtime pin files.
+type PinOptions struct {
+	// ToolMap maps the tool names to vector aliases. An empty alias ignores the tool.
+	// Tools without mapping use the ToolAliases or their normalized name.
+	ToolMap []string `name:"tool" placeholder:"TOOL=ALIAS" help:"map the tool name to a vector alias, or ignore the tool if the alias is empty"`
+}
+
+// FromPins builds a tuplip source from the given runtime pin files.
+// Files named like one of the PinFiles contain the version of a single tool. All other files are read in the
+// format of the ToolVersionsFile. If no files are given, the known pin files in the working directory are used.
+// If multiple files pin the same tool, the version of the first file is used.
+func (t *Tuplip) FromPins(files []string, options PinOptions) (source *TuplipSource, err error) {
+	if len(files) == 0 {
+		if files, err = findPinFiles("."); err != nil {
+			return nil, err
+		}
+	}
+	mapping, err := parseAliasMap(options.ToolMap, "TOOL=ALIAS")
+	if err != nil {
+		return nil, err
+	}
+	var vectors []string
+	pinned := make(map[string]string)
+	for _, file := range files {
+		logger.InfoWith("queueing read from pin file").
+			String("file", file).
+			Write()
+		pins, err := readPinFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, pin := range pins {
+			alias, ok := mapping[pin.tool]
+			if !ok {
+				if alias, ok = ToolAliases[pin.tool]; !ok {
+					alias = normalizeAlias(pin.tool)
+				}
+			}
+			if alias == "" {
+				continue
+			}
+			if version, ok := pinned[alias]; ok {
+				if version != pin.version {
+					logger.WarnWith("the tool is pinned to different versions").
+						String("alias", alias).
+						String("version", version).
+						String("ignored version", pin.version).
+						String("file", file).
+						Write()
+				}
+				continue
+			}
+			pinned[alias] = pin.version
+			vectors = append(vectors, alias+VersionSeparator+pin.version)
+		}
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("the pin files %v do not yield any tag vectors", files)
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm}, nil
+}
+
+// findPinFiles returns the known pin files that exist in the given directory.
+// The ToolVersionsFile comes first, followed by the PinFiles in lexical order.
+func findPinFiles(dir string) (files []string, err error) {
+	var names []string
+	for name := range PinFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range append([]string{ToolVersionsFile}, names...) {
+		file := filepath.Join(dir, name)
+		if _, err = os.Stat(file); err == nil {
+			files = append(files, file)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no pin files could be found in '%s'", dir)
+	}
+	return files, nil
+}
+
+// pin is a tool version that is pinned in a pin file.
+type pin struct {
+	tool    string
+	version string
+}
+
+// readPinFile reads the tool versions of the given pin file.
+// Blank lines and comments starting with `#` are skipped. If a tool has multiple versions, the first one is used.
+// Versions that do not start with a digit (e.g., `system` or `lts/*`) are skipped.
+func readPinFile(file string) (pins []pin, err error) {
+	handle, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer handle.Close()
+	singleTool, isSingle := PinFiles[filepath.Base(file)]
+	scanner := bufio.NewScanner(handle)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		var entry pin
+		if isSingle {
+			entry = pin{tool: singleTool, version: fields[0]}
+		} else if len(fields) < 2 {
+			return nil, fmt.Errorf("%s line %d: the tool '%s' has no version", file, line, fields[0])
+		} else {
+			entry = pin{tool: fields[0], version: fields[1]}
+		}
+		raw := entry.version
+		entry.version = vectorVersion(raw)
+		if entry.version == "" || !unicode.IsDigit(rune(entry.version[0])) {
+			logger.WarnWith("skipping unsupported tool version").
+				String("tool", entry.tool).
+				String("version", raw).
+				String("file", file).
+				Write()
+		} else {
+			pins = append(pins, entry)
+		}
+		if isSingle {
+			break
+		}
+	}
+	return pins, scanner.Err()
+}
@@ -0,0 +1,93 @@
+package tupliplib
+
+import (
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTuplip_FromPins(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       []string
+		options     PinOptions
+		wantVectors []interface{}
+		wantErr     bool
+	}{
+		{
+			name:        "Tool Versions",
+			files:       []string{"../../test/pins/.tool-versions"},
+			wantVectors: []interface{}{"node:20.11.0", "python:3.12.1", "golang:1.22.3"},
+		},
+		{
+			name: "Single Tool Files",
+			files: []string{
+				"../../test/pins/.nvmrc",
+				"../../test/pins/.python-version",
+				"../../test/pins/.java-version",
+			},
+			wantVectors: []interface{}{"node:18.19.0", "python:3.11.7", "java:17.0.10"},
+		},
+		{
+			name:        "First File Wins",
+			files:       []string{"../../test/pins/.tool-versions", "../../test/pins/.nvmrc", "../../test/pins/.java-version"},
+			wantVectors: []interface{}{"node:20.11.0", "python:3.12.1", "golang:1.22.3", "java:17.0.10"},
+		},
+		{
+			name:        "Tool Mapping",
+			files:       []string{"../../test/pins/.tool-versions", "../../test/pins/.java-version"},
+			options:     PinOptions{ToolMap: []string{"java=openjdk", "golang=", "nodejs=nodejs"}},
+			wantVectors: []interface{}{"nodejs:20.11.0", "python:3.12.1", "openjdk:17.0.10"},
+		},
+		{
+			name:    "Unsupported Version",
+			files:   []string{"../../test/pins/lts/.nvmrc"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Mapping",
+			files:   []string{"../../test/pins/.nvmrc"},
+			options: PinOptions{ToolMap: []string{"nodejs"}},
+			wantErr: true,
+		},
+		{
+			name:    "Missing File",
+			files:   []string{"../../test/pins/.ruby-version"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).FromPins(tt.files, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromPins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.FromPins() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
+
+func Test_findPinFiles(t *testing.T) {
+	got, err := findPinFiles("../../test/pins")
+	if err != nil {
+		t.Fatalf("findPinFiles() error = %v", err)
+	}
+	want := []string{
+		"../../test/pins/.tool-versions",
+		"../../test/pins/.java-version",
+		"../../test/pins/.nvmrc",
+		"../../test/pins/.python-version",
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("findPinFiles() = %v, want %v", got, want)
+	}
+	if _, err = findPinFiles("../../test/images"); err == nil {
+		t.Errorf("findPinFiles() without pin files did not fail")
+	}
+}
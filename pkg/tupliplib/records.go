@@ -0,0 +1,86 @@
+package tupliplib
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+// InputFormat depicts the format of the tag vectors that are read from a reader.
+type InputFormat string
+
+const (
+	// TextInput separates the tag vectors by line breaks and a separator.
+	TextInput InputFormat = "text"
+	// JSONLinesInput contains one JSON-encoded VectorSpec per line.
+	JSONLinesInput InputFormat = "jsonl"
+	// NULInput separates the tag vectors by NUL characters.
+	NULInput InputFormat = "nul"
+)
+
+// FromJSONLines builds a tuplip source from a reader that contains one JSON-encoded VectorSpec per line
+// (e.g., `{"alias": "alpine", "version": "3.8", "options": {"exclude-minor": true}}`). Blank lines are skipped.
+// The records are read and validated immediately. Invalid records fail with their line numbers.
+func (t *Tuplip) FromJSONLines(src io.Reader) (source *TuplipSource, err error) {
+	logger.Info("queueing read from JSON lines")
+	var vectors []string
+	options := make(map[string]VectorOptions)
+	scanner := bufio.NewScanner(src)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var vector VectorSpec
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(&vector); err != nil {
+			return nil, fmt.Errorf("line %d: invalid vector record: %v", line, err)
+		}
+		if decoder.More() {
+			return nil, fmt.Errorf("line %d: invalid vector record: a line must contain a single JSON object", line)
+		}
+		if err = vector.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: invalid vector record: %v", line, err)
+		}
+		vectors = append(vectors, vector.String())
+		options[vector.alias()] = vector.Options
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm, options: options}, nil
+}
+
+// FromNUL builds a tuplip source from a reader that separates the tag vectors by NUL characters
+// (e.g., the output of `find -print0`). Surrounding white space is removed from the vectors.
+// References to environment variables in the tag vectors are interpolated.
+func (t *Tuplip) FromNUL(src io.Reader) *TuplipSource {
+	logger.Info("queueing read from NUL-separated reader")
+	stm := stream.New(emitters.Scanner(src, scanNUL))
+	stm.Map(strings.TrimSpace)
+	stm.Map(interpolate)
+	stm.Filter(nonEmpty)
+	return &TuplipSource{tuplip: t, stream: stm}
+}
+
+// scanNUL is a bufio.SplitFunc that splits the input at NUL characters.
+func scanNUL(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,87 @@
+package tupliplib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTuplip_FromJSONLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantVectors []interface{}
+		wantOptions map[string]VectorOptions
+		wantErr     string
+	}{
+		{
+			name: "Records",
+			input: `{"version": "1.0.0"}
+{"alias": "alpine", "version": "3.8", "options": {"exclude-minor": true}}
+
+{"kind": "alias", "alias": "slim"}
+`,
+			wantVectors: []interface{}{"_:1.0.0", "alpine:3.8", "slim"},
+			wantOptions: map[string]VectorOptions{
+				"_":      {},
+				"alpine": {ExcludeMinor: true},
+				"slim":   {},
+			},
+		},
+		{
+			name:    "Invalid JSON",
+			input:   "{\"alias\": \"alpine\"}\n{\"alias\": alpine}",
+			wantErr: "line 2: invalid vector record: invalid character",
+		},
+		{
+			name:    "Unknown Field",
+			input:   `{"alias": "alpine", "tag": "3.8"}`,
+			wantErr: `line 1: invalid vector record: json: unknown field "tag"`,
+		},
+		{
+			name:    "Multiple Objects",
+			input:   `{"alias": "alpine"} {"alias": "slim"}`,
+			wantErr: "line 1: invalid vector record: a line must contain a single JSON object",
+		},
+		{
+			name:    "Inconsistent Kind",
+			input:   `{"kind": "alias", "alias": "alpine", "version": "3.8"}`,
+			wantErr: "line 1: invalid vector record: version: must be empty for alias vectors",
+		},
+		{
+			name:    "Invalid Alias",
+			input:   `{"alias": "alpine linux", "version": "3.8"}`,
+			wantErr: "line 1: invalid vector record: alias: must be in a valid format",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).FromJSONLines(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Tuplip.FromJSONLines() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Tuplip.FromJSONLines() error = %v", err)
+			}
+			if !cmp.Equal(src.options, tt.wantOptions) {
+				t.Errorf("Tuplip.FromJSONLines() options = %v, want %v", src.options, tt.wantOptions)
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.FromJSONLines() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
+
+func TestTuplip_FromNUL(t *testing.T) {
+	t.Setenv("TUPLIP_TEST_ALPINE", "3.19")
+	src := new(Tuplip).FromNUL(strings.NewReader("_:1.0\x00alpine:${TUPLIP_TEST_ALPINE}\x00\x00 slim \n\x00foo"))
+	want := mapset.NewSet("_:1.0", "alpine:3.19", "slim", "foo")
+	if got := collectVectors(t, src); !got.Equal(want) {
+		t.Errorf("Tuplip.FromNUL() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package tupliplib
+
+import (
+	_ "crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+// imageReference is a Docker image reference split into its components.
+type imageReference struct {
+	// domain is the registry host including the port (e.g., `localhost:5000`). It is empty for the default registry.
+	domain string
+	// path is the repository path without the domain (e.g., `org/image`).
+	path string
+	// tag is the image tag.
+	tag string
+	// digest is the content digest that pins the image (e.g., `sha256:...`).
+	digest string
+}
+
+// name returns the last path component of the repository, i.e., the image name without organization.
+func (r imageReference) name() string {
+	return r.path[strings.LastIndex(r.path, RepositorySeparator)+1:]
+}
+
+// repository returns the repository including the domain if it is not the default registry.
+func (r imageReference) repository() string {
+	if r.domain == "" {
+		return r.path
+	}
+	return r.domain + RepositorySeparator + r.path
+}
+
+// parseReference splits the given image reference using the Docker reference grammar.
+// References that do not match the grammar (e.g., wildcard dependencies) are split tolerantly by the same rules.
+func parseReference(image string) (ref imageReference) {
+	if parsed, err := reference.Parse(image); err == nil {
+		if named, ok := parsed.(reference.Named); ok {
+			ref.domain, ref.path = splitDomain(named.Name())
+		}
+		if tagged, ok := parsed.(reference.Tagged); ok {
+			ref.tag = tagged.Tag()
+		}
+		if digested, ok := parsed.(reference.Digested); ok {
+			ref.digest = digested.Digest().String()
+		}
+		return
+	}
+	remainder := image
+	if index := strings.Index(remainder, DigestSeparator); index >= 0 {
+		remainder, ref.digest = remainder[:index], remainder[index+1:]
+	}
+	if index := strings.LastIndex(remainder, VersionSeparator); index > strings.LastIndex(remainder, RepositorySeparator) {
+		remainder, ref.tag = remainder[:index], remainder[index+1:]
+	}
+	ref.domain, ref.path = splitDomain(remainder)
+	return
+}
+
+// checkReference fails if the given base image reference of the FROM instruction in the given line does not match the
+// Docker reference grammar. An empty image name (e.g., of an undeclared ARG in `${IMAGE}:1.0`) fails as well.
+// Wildcard dependencies are always valid.
+func checkReference(image string, line int) error {
+	ref := parseReference(image)
+	if ref.path == WildcardDependency && ref.domain == "" {
+		return nil
+	}
+	if ref.path == "" {
+		return fmt.Errorf("line %d: the image reference '%s' has no image name; check that its ARGs are declared",
+			line, image)
+	}
+	if _, err := reference.Parse(image); err != nil {
+		return fmt.Errorf("line %d: the image reference '%s' is invalid: %v", line, image, err)
+	}
+	return nil
+}
+
+// splitDomain splits the registry domain from the given repository name like Docker does it.
+// The first path component is only a domain if it contains a dot or a port, is localhost, or contains upper-case
+// characters.
+func splitDomain(name string) (domain string, path string) {
+	index := strings.Index(name, RepositorySeparator)
+	if index < 0 {
+		return "", name
+	}
+	domain = name[:index]
+	if strings.ContainsAny(domain, ".:") || domain == DockerLocalhost || strings.ToLower(domain) != domain {
+		return domain, name[index+1:]
+	}
+	return "", name
+}
@@ -0,0 +1,120 @@
+package tupliplib
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_parseReference(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name     string
+		image    string
+		want     imageReference
+		wantName string
+	}{
+		{
+			name:     "Official Image",
+			image:    "golang:1.22",
+			want:     imageReference{path: "golang", tag: "1.22"},
+			wantName: "golang",
+		},
+		{
+			name:     "Organization Image",
+			image:    "gofunky/git",
+			want:     imageReference{path: "gofunky/git"},
+			wantName: "git",
+		},
+		{
+			name:     "Registry Image",
+			image:    "ghcr.io/org/image:1.0",
+			want:     imageReference{domain: "ghcr.io", path: "org/image", tag: "1.0"},
+			wantName: "image",
+		},
+		{
+			name:     "Registry With Port",
+			image:    "localhost:5000/img:1",
+			want:     imageReference{domain: "localhost:5000", path: "img", tag: "1"},
+			wantName: "img",
+		},
+		{
+			name:     "Localhost Registry",
+			image:    "localhost/img",
+			want:     imageReference{domain: "localhost", path: "img"},
+			wantName: "img",
+		},
+		{
+			name:     "Tag And Digest",
+			image:    "image:1.2@" + digest,
+			want:     imageReference{path: "image", tag: "1.2", digest: digest},
+			wantName: "image",
+		},
+		{
+			name:     "Digest Only",
+			image:    "docker.io/library/debian@" + digest,
+			want:     imageReference{domain: "docker.io", path: "library/debian", digest: digest},
+			wantName: "debian",
+		},
+		{
+			name:     "Wildcard Dependency",
+			image:    "_:1.2.3",
+			want:     imageReference{path: "_", tag: "1.2.3"},
+			wantName: "_",
+		},
+		{
+			name:     "Invalid Grammar With Port",
+			image:    "Registry:5000/Org/Image:1.0",
+			want:     imageReference{domain: "Registry:5000", path: "Org/Image", tag: "1.0"},
+			wantName: "Image",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseReference(tt.image)
+			if got != tt.want {
+				t.Errorf("parseReference() = %+v, want %+v", got, tt.want)
+			}
+			if gotName := got.name(); gotName != tt.wantName {
+				t.Errorf("imageReference.name() = %v, want %v", gotName, tt.wantName)
+			}
+		})
+	}
+}
+
+func Test_checkReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		wantErr string
+	}{
+		{name: "Valid Image", image: "ghcr.io/gofunky/golang:1.22.3-alpine3.19"},
+		{name: "Scratch", image: "scratch"},
+		{name: "Wildcard Dependency", image: "_:1.2.3"},
+		{
+			name:    "Empty Image Name",
+			image:   ":1.0",
+			wantErr: "line 3: the image reference ':1.0' has no image name; check that its ARGs are declared",
+		},
+		{
+			name:    "Multiple Tags",
+			image:   "a:b:c",
+			wantErr: "line 3: the image reference 'a:b:c' is invalid: invalid reference format",
+		},
+		{
+			name:    "Upper-Case Path",
+			image:   "Registry:5000/Org/Image:1.0",
+			wantErr: "line 3: the image reference 'Registry:5000/Org/Image:1.0' is invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkReference(tt.image, 3)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("checkReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("checkReference() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
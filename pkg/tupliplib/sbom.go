@@ -0,0 +1,226 @@
+package tupliplib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/gofunky/automi/emitters"
+	"github.com/gofunky/automi/stream"
+)
+
+const (
+	// purlPrefix is the scheme of package URLs.
+	purlPrefix = "pkg:"
+	// spdxOperatingSystem is the SPDX primary package purpose of operating systems.
+	spdxOperatingSystem = "OPERATING-SYSTEM"
+	// cycloneDXOperatingSystem is the CycloneDX component type of operating systems.
+	cycloneDXOperatingSystem = "operating-system"
+	// cycloneDXFormat is the bomFormat of CycloneDX documents.
+	cycloneDXFormat = "CycloneDX"
+)
+
+// SBOMOptions contain the parameters for reading tag vectors from a software bill of materials.
+type SBOMOptions struct {
+	// Packages are glob patterns of the package names that yield dependency vectors, optionally followed by an alias
+	// after an equation sign. Patterns starting with `pkg:` match the package URL without version, qualifiers, and
+	// subpath instead of the name. The alias defaults to the normalized package name.
+	Packages []string `name:"package" placeholder:"PATTERN[=ALIAS]" help:"add the versions of the packages whose name or purl (starting with 'pkg:') matches the glob pattern as dependency vectors"`
+	// OS adds the operating system of the SBOM as dependency vector.
+	OS bool `name:"os" help:"add the operating system of the SBOM as dependency vector"`
+}
+
+// sbomPackage is a package of a software bill of materials.
+type sbomPackage struct {
+	name    string
+	version string
+	purl    string
+	os      bool
+}
+
+// spdxDocument is the part of an SPDX JSON document that is relevant for tag vectors.
+type spdxDocument struct {
+	SPDXVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name         string `json:"name"`
+		VersionInfo  string `json:"versionInfo"`
+		Purpose      string `json:"primaryPackagePurpose"`
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// cycloneDXComponent is the part of a CycloneDX component that is relevant for tag vectors.
+type cycloneDXComponent struct {
+	Type       string               `json:"type"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	PURL       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// cycloneDXDocument is the part of a CycloneDX JSON document that is relevant for tag vectors.
+type cycloneDXDocument struct {
+	BOMFormat  string               `json:"bomFormat"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// FromSBOM builds a tuplip source from a software bill of materials in the SPDX or CycloneDX JSON format.
+// The packages that match the patterns of the given options yield dependency vectors with their versions.
+// If multiple packages yield the same alias, the version of the first package is used.
+func (t *Tuplip) FromSBOM(src string, options SBOMOptions) (source *TuplipSource, err error) {
+	logger.InfoWith("queueing read from SBOM").
+		String("file", src).
+		Write()
+	content, err := ioutil.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := readSBOM(content)
+	if err != nil {
+		return nil, fmt.Errorf("the SBOM '%s' could not be read: %v", src, err)
+	}
+	var vectors []string
+	included := make(map[string]string)
+	include := func(alias string, pkg sbomPackage) {
+		version := packageVersion(pkg.version)
+		if version == "" || !unicode.IsDigit(rune(version[0])) {
+			logger.WarnWith("skipping unsupported package version").
+				String("package", pkg.name).
+				String("version", pkg.version).
+				Write()
+			return
+		}
+		if existing, ok := included[alias]; ok {
+			if existing != version {
+				logger.WarnWith("multiple packages yield the same alias").
+					String("alias", alias).
+					String("version", existing).
+					String("ignored version", version).
+					String("package", pkg.name).
+					Write()
+			}
+			return
+		}
+		included[alias] = version
+		vectors = append(vectors, alias+VersionSeparator+version)
+	}
+	if options.OS {
+		var found bool
+		for _, pkg := range packages {
+			if pkg.os {
+				found = true
+				include(normalizeAlias(pkg.name), pkg)
+			}
+		}
+		if !found {
+			logger.WarnWith("the SBOM does not contain an operating system").
+				String("file", src).
+				Write()
+		}
+	}
+	for _, entry := range options.Packages {
+		pattern, alias, _ := strings.Cut(entry, ArgEquation)
+		if alias != "" && normalizeAlias(alias) != alias {
+			return nil, fmt.Errorf("the package mapping '%s' has an invalid alias '%s'", entry, alias)
+		}
+		var found bool
+		for _, pkg := range packages {
+			if matched, err := pkg.matches(pattern); err != nil {
+				return nil, fmt.Errorf("invalid package pattern '%s': %v", pattern, err)
+			} else if !matched {
+				continue
+			}
+			found = true
+			packageAlias := alias
+			if packageAlias == "" {
+				packageAlias = normalizeAlias(pkg.name)
+			}
+			include(packageAlias, pkg)
+		}
+		if !found {
+			logger.WarnWith("no package matches the pattern").
+				String("package", pattern).
+				Write()
+		}
+	}
+	if len(vectors) == 0 {
+		return nil, fmt.Errorf("the SBOM '%s' does not yield any tag vectors", src)
+	}
+	stm := stream.New(emitters.Slice(vectors))
+	return &TuplipSource{tuplip: t, stream: stm}, nil
+}
+
+// readSBOM reads the packages of the given SPDX or CycloneDX JSON document.
+func readSBOM(content []byte) (packages []sbomPackage, err error) {
+	var spdx spdxDocument
+	if err = json.Unmarshal(content, &spdx); err != nil {
+		return nil, err
+	}
+	if spdx.SPDXVersion != "" {
+		for _, pkg := range spdx.Packages {
+			entry := sbomPackage{name: pkg.Name, version: pkg.VersionInfo, os: pkg.Purpose == spdxOperatingSystem}
+			for _, ref := range pkg.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					entry.purl = ref.ReferenceLocator
+					break
+				}
+			}
+			packages = append(packages, entry)
+		}
+		return packages, nil
+	}
+	var cycloneDX cycloneDXDocument
+	if err = json.Unmarshal(content, &cycloneDX); err != nil {
+		return nil, err
+	}
+	if cycloneDX.BOMFormat != cycloneDXFormat {
+		return nil, fmt.Errorf("neither an SPDX nor a CycloneDX JSON document")
+	}
+	var walk func(components []cycloneDXComponent)
+	walk = func(components []cycloneDXComponent) {
+		for _, component := range components {
+			packages = append(packages, sbomPackage{
+				name:    component.Name,
+				version: component.Version,
+				purl:    component.PURL,
+				os:      component.Type == cycloneDXOperatingSystem,
+			})
+			walk(component.Components)
+		}
+	}
+	walk(cycloneDX.Components)
+	return packages, nil
+}
+
+// matches checks if the package matches the given glob pattern.
+// Patterns starting with `pkg:` are matched against the package URL without version, qualifiers, and subpath.
+func (p sbomPackage) matches(pattern string) (bool, error) {
+	if !strings.HasPrefix(pattern, purlPrefix) {
+		return path.Match(pattern, p.name)
+	}
+	if p.purl == "" {
+		return false, nil
+	}
+	purl, _, _ := strings.Cut(p.purl, "#")
+	purl, _, _ = strings.Cut(purl, "?")
+	purl, _, _ = strings.Cut(purl, "@")
+	return path.Match(pattern, purl)
+}
+
+// packageVersion converts the given package version to a vector version.
+// The epoch (e.g., `1:` in Debian versions), the package revision (e.g., `-r4` in Alpine versions), and the build
+// metadata are removed since they are not valid in tag vectors.
+func packageVersion(version string) string {
+	if _, rest, ok := strings.Cut(version, ":"); ok {
+		version = rest
+	}
+	version, _, _ = strings.Cut(version, "-")
+	version, _, _ = strings.Cut(version, "~")
+	return vectorVersion(version)
+}
@@ -0,0 +1,103 @@
+package tupliplib
+
+import (
+	"testing"
+
+	"github.com/gofunky/pyraset/v2"
+)
+
+func TestTuplip_FromSBOM(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		options     SBOMOptions
+		wantVectors []interface{}
+		wantErr     bool
+	}{
+		{
+			name:        "SPDX Operating System",
+			file:        "../../test/sbom/image.spdx.json",
+			options:     SBOMOptions{OS: true},
+			wantVectors: []interface{}{"alpine:3.19.1"},
+		},
+		{
+			name: "SPDX Packages",
+			file: "../../test/sbom/image.spdx.json",
+			options: SBOMOptions{Packages: []string{
+				"openssl",
+				"pkg:generic/nodejs=node",
+				"busybox",
+				"unknown",
+			}},
+			wantVectors: []interface{}{"openssl:3.1.4", "node:20.11.0"},
+		},
+		{
+			name:        "SPDX Purl Pattern",
+			file:        "../../test/sbom/image.spdx.json",
+			options:     SBOMOptions{Packages: []string{"pkg:apk/alpine/*ssl*=ssl"}},
+			wantVectors: []interface{}{"ssl:3.1.4"},
+		},
+		{
+			name: "CycloneDX",
+			file: "../../test/sbom/image.cdx.json",
+			options: SBOMOptions{OS: true, Packages: []string{
+				"pkg:deb/debian/*",
+				"pip",
+			}},
+			wantVectors: []interface{}{"debian:12", "openssl:3.0.11", "tzdata:2024a", "pip:23.2.1"},
+		},
+		{
+			name:    "No Vectors",
+			file:    "../../test/sbom/image.cdx.json",
+			options: SBOMOptions{Packages: []string{"unknown"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Alias",
+			file:    "../../test/sbom/image.cdx.json",
+			options: SBOMOptions{Packages: []string{"python=py-thon"}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown Format",
+			file:    "../../test/tuplip.yaml",
+			options: SBOMOptions{OS: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := new(Tuplip).FromSBOM(tt.file, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Tuplip.FromSBOM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := collectVectors(t, src); !got.Equal(mapset.NewSet(tt.wantVectors...)) {
+				t.Errorf("Tuplip.FromSBOM() = %v, want %v", got, tt.wantVectors)
+			}
+		})
+	}
+}
+
+func Test_packageVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"3.1.4-r5", "3.1.4"},
+		{"1:2.36.1-8+deb11u1", "2.36.1"},
+		{"3.0.11-1~deb12u2", "3.0.11"},
+		{"v20.11.0", "20.11.0"},
+		{"1.2.3+dfsg", "1.2.3"},
+		{"12", "12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := packageVersion(tt.version); got != tt.want {
+				t.Errorf("packageVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package tupliplib
 
-import "github.com/gofunky/pyraset/v2"
+import (
+	"strings"
+
+	"github.com/gofunky/pyraset/v2"
+)
 
 // SortedSet is a a slice of map sets to make them sortable.
 // It will be sorted alphabetically by the string representations of the subsets.
@@ -14,3 +18,36 @@ func (a SortedSet) Less(i, j int) bool { return a[i].String() > a[j].String() }
 
 // Swap swaps the two slice elements at the given positions.
 func (a SortedSet) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+
+// SortedTags is a slice of Docker tags that is sorted by their specificity, the most specific tags first.
+// Tags of the same specificity are sorted lexically.
+type SortedTags []string
+
+// Len gives the size of the slice.
+func (a SortedTags) Len() int { return len(a) }
+
+// Less orders the more specific tag first, or the lexically smaller tag if both are equally specific.
+func (a SortedTags) Less(i, j int) bool {
+	left, right := TagSpecificity(a[i]), TagSpecificity(a[j])
+	if left != right {
+		return left > right
+	}
+	return a[i] < a[j]
+}
+
+// Swap swaps the two slice elements at the given positions.
+func (a SortedTags) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+
+// TagSpecificity returns the number of tag vectors and version components of the given Docker tag
+// (e.g., 4 for `1.22.3-bookworm`). The repository of the tag is ignored.
+func TagSpecificity(tag string) (specificity int) {
+	if index := strings.LastIndex(tag, VersionSeparator); index > strings.LastIndex(tag, RepositorySeparator) {
+		tag = tag[index+1:]
+	}
+	for _, vector := range strings.Split(tag, DockerTagSeparator) {
+		if vector != "" {
+			specificity += 1 + strings.Count(vector, VersionDot)
+		}
+	}
+	return specificity
+}
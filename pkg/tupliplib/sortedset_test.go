@@ -0,0 +1,35 @@
+package tupliplib
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSortedTags(t *testing.T) {
+	tags := SortedTags{"latest", "1", "1.0-alpine", "alpine3.8", "1.0.0", "gofunky/app:1.0.0-alpine3.8", "1-alpine"}
+	sort.Sort(tags)
+	want := SortedTags{"gofunky/app:1.0.0-alpine3.8", "1.0-alpine", "1.0.0", "1-alpine", "alpine3.8", "1", "latest"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("sort.Sort(SortedTags) = %v, want %v", tags, want)
+	}
+}
+
+func TestTagSpecificity(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want int
+	}{
+		{"1.22.3-bookworm", 4},
+		{"localhost:5000/gofunky/app:1.0-alpine3", 3},
+		{"latest", 1},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := TagSpecificity(tt.tag); got != tt.want {
+				t.Errorf("TagSpecificity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
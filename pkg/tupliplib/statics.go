@@ -37,18 +37,6 @@ func nonEmpty(input string) bool {
 	return input != ""
 }
 
-// hasPrefix marks if a line has the given prefix.
-func hasPrefix(prefix string) func(input string) bool {
-	return func(input string) bool {
-		return strings.HasPrefix(input, prefix)
-	}
-}
-
-// withoutFrom removes the FROM instruction prefix.
-func withoutFrom(input string) string {
-	return strings.TrimSpace(strings.TrimPrefix(input, DockerFromInstruction))
-}
-
 // withoutWildcard removes vector-like colon concatenation from tags.
 func withoutColons(input string) string {
 	return strings.Replace(input, ":", "", 1)
@@ -59,60 +47,43 @@ func withoutWildcard(input string) string {
 	return strings.TrimSpace(strings.TrimPrefix(input, WildcardDependency+VersionSeparator))
 }
 
-// splitFromInstruction splits up a FROM instruction to the repository, the tag, and the alias.
-func splitFromInstruction(inst string) (repo string, version string, alias string) {
-	withoutFrom := withoutFrom(inst)
-	aliasTuple := strings.SplitN(withoutFrom, DockerAs, 2)
-	if len(aliasTuple) > 1 {
-		alias = strings.TrimSpace(aliasTuple[1])
-	}
-	repoTuple := strings.SplitN(aliasTuple[0], VersionSeparator, 2)
-	repo = strings.TrimSpace(repoTuple[0])
-	if len(repoTuple) > 1 {
-		version = strings.TrimSpace(repoTuple[1])
-	}
-	return
+// isBaseImage marks FROM instructions that are not based on an earlier build stage.
+func isBaseImage(inst instruction) bool {
+	return inst.command == DockerFromInstruction && !inst.stageRef
 }
 
-// transformRootArgument matches the version of the root tag vector from the Dockerfile's VERSION ARG
-// and transforms it to a FROM instruction.
-// If remove is true, the instruction will be removed instead of transformed.
-func transformRootVersion(remove bool) func(inst string) string {
-	return func(inst string) string {
-		if hasPrefix(VersionInstruction)(inst) {
-			if remove {
-				return ""
-			}
-			version := strings.TrimSpace(
-				strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(inst, VersionInstruction)), ArgEquation),
-			)
-			return WildcardInstruction + version
-		}
-		return inst
-	}
+// rootInstruction builds a FROM instruction with a wildcard dependency for the given root version.
+func rootInstruction(version string) instruction {
+	return instruction{command: DockerFromInstruction, args: []string{WildcardDependency + VersionSeparator + version}}
 }
 
 // toTagVector converts the given FROM instruction to a tag vector.
-func toTagVector(inst string) (vector []string) {
+// An alias directive overrides the alias that is derived from the image name.
+// The tag of a base image is parsed by the first matching extraction rule. Otherwise, and for the root tag vector, it is
+// split by dashes, and each part is split at its first digit.
+func (c Conventions) toTagVector(inst instruction) (vector []string) {
 	vector = make([]string, 0)
-	if inst == ScratchInstruction {
+	var firstVector string
+	image, alias := inst.image(), inst.stageName()
+	if image == DockerScratch && alias == "" && inst.directive.alias == "" {
 		return
 	}
-	var firstVector string
-	repository, version, alias := splitFromInstruction(inst)
-	if strings.HasPrefix(alias, IgnoredAliasPrefix) {
+	ref := parseReference(image)
+	if c.isIgnored(alias) || inst.directive.ignore {
 		return
-	} else if repository == DockerScratch && alias != "" {
+	} else if inst.directive.alias != "" {
+		firstVector = inst.directive.alias
+	} else if ref.domain == "" && ref.path == DockerScratch && alias != "" {
 		firstVector = alias
 	} else {
-		repoParts := strings.SplitN(repository, RepositorySeparator, 2)
-		if len(repoParts) > 1 {
-			firstVector = repoParts[1]
-		} else {
-			firstVector = repoParts[0]
+		firstVector = ref.name()
+	}
+	if ref.name() != WildcardDependency {
+		if extracted, ok := c.extract(ref.name(), ref.tag, firstVector); ok {
+			return extracted
 		}
 	}
-	parts := strings.Split(version, DockerTagSeparator)
+	parts := strings.Split(ref.tag, DockerTagSeparator)
 	firstVersion := parts[0]
 	if firstVersion != "" {
 		firstVector = strings.Join([]string{firstVector, parts[0]}, VersionSeparator)
@@ -133,25 +104,73 @@ func toTagVector(inst string) (vector []string) {
 	return
 }
 
-// findRepository checks if the given Dockerfile lines are from a valid Dockerfile and returns the REPOSITORY ARG
-// if given.
-func findRepository(lines []string) (repository string, err error) {
+// checkBaseImages fails if the reference of a base image of the given FROM instructions is invalid.
+func checkBaseImages(instructions []instruction) error {
+	for _, inst := range instructions {
+		if !isBaseImage(inst) {
+			continue
+		}
+		if err := checkReference(inst.image(), inst.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findDigests maps the aliases of the given FROM instructions to the digests that pin their base images.
+func (c Conventions) findDigests(instructions []instruction) (digests map[string]string) {
+	digests = make(map[string]string)
+	for _, inst := range instructions {
+		if !isBaseImage(inst) {
+			continue
+		}
+		if ref := parseReference(inst.image()); ref.digest != "" {
+			if vector := c.toTagVector(inst); len(vector) > 0 {
+				digests[strings.SplitN(vector[0], VersionSeparator, 2)[0]] = ref.digest
+			}
+		}
+	}
+	return
+}
+
+// findOptions maps the aliases of the given FROM instructions to the vector options of their tuplip directives.
+func (c Conventions) findOptions(instructions []instruction) (options map[string]VectorOptions) {
+	options = make(map[string]VectorOptions)
+	for _, inst := range instructions {
+		if !isBaseImage(inst) || inst.directive.options == (VectorOptions{}) {
+			continue
+		}
+		if vector := c.toTagVector(inst); len(vector) > 0 {
+			options[strings.SplitN(vector[0], VersionSeparator, 2)[0]] = inst.directive.options
+		}
+	}
+	return
+}
+
+// findRepository checks if the given Dockerfile instructions are from a valid Dockerfile and returns
+// the value of the repository ARG if given.
+func (c Conventions) findRepository(instructions []instruction) (repository string, err error) {
 	var hasVectors bool
-	for _, l := range lines {
-		if hasPrefix(DockerArgInstruction + Space + VersionArg)(l) {
+	for _, inst := range instructions {
+		if _, declared := inst.argValue(c.versionArg()); declared {
 			hasVectors = true
 		}
-		if hasPrefix(RepositoryInstruction)(l) {
-			repository = strings.TrimSpace(
-				strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(l, RepositoryInstruction)), ArgEquation),
-			)
+		if value, declared := inst.argValue(c.repositoryArg()); declared {
+			repository = value
 		}
-		if hasPrefix(DockerFromInstruction)(l) {
+		if inst.command == DockerFromInstruction {
 			hasVectors = true
 		}
+		if inst.command == DockerArgInstruction {
+			for _, pair := range inst.pairs() {
+				if _, ok := c.vectorArg(pair); ok {
+					hasVectors = true
+				}
+			}
+		}
 	}
 	if !hasVectors {
-		err = errors.New("the given Dockerfile does not contain any FROM instructions or VERSION ARG")
+		err = fmt.Errorf("the given Dockerfile does not contain any FROM instructions or %s ARG", c.versionArg())
 		return
 	}
 	return
@@ -3,6 +3,7 @@ package tupliplib
 import (
 	"github.com/gofunky/pyraset/v2"
 	"github.com/google/go-cmp/cmp"
+	"strings"
 	"testing"
 )
 
@@ -402,18 +403,34 @@ func Test_markRootInstruction(t *testing.T) {
 	tests := []struct {
 		name           string
 		args           args
+		conventions    Conventions
 		wantRepository string
-		wantErr        bool
+		wantErr        string
 	}{
 		{
 			name:    "Empty",
-			wantErr: true,
+			wantErr: "FROM instructions or VERSION ARG",
 		},
 		{
 			name:    "Missing Vectors",
-			wantErr: true,
+			wantErr: "FROM instructions or VERSION ARG",
 			args:    args{lines: []string{"", "ARG", "NOFROM"}},
 		},
+		{
+			name:        "Missing Custom Version ARG",
+			conventions: Conventions{VersionArg: "RELEASE"},
+			wantErr:     "FROM instructions or RELEASE ARG",
+			args:        args{lines: []string{"ARG VERSION=1.2.3"}},
+		},
+		{
+			name:        "Custom ARGs",
+			conventions: Conventions{VersionArg: "RELEASE", RepositoryArg: "TARGET"},
+			args: args{lines: []string{
+				"ARG TARGET=gofunky/docker",
+				"ARG RELEASE=1.2.3",
+			}},
+			wantRepository: "gofunky/docker",
+		},
 		{
 			name: "Only ARGs",
 			args: args{lines: []string{
@@ -436,8 +453,12 @@ func Test_markRootInstruction(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRepository, err := findRepository(tt.args.lines)
-			if (err != nil) != tt.wantErr {
+			instructions, err := parseDockerfile(strings.Join(tt.args.lines, "\n"))
+			if err != nil {
+				t.Fatalf("parseDockerfile() error = %v", err)
+			}
+			gotRepository, err := tt.conventions.findRepository(instructions)
+			if (err != nil) != (tt.wantErr != "") || err != nil && !strings.Contains(err.Error(), tt.wantErr) {
 				t.Errorf("findRepository() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -512,6 +533,21 @@ func Test_toTagVector(t *testing.T) {
 			args:       args{"FROM gofunky/golang:1.11.0-alpine3.8 as builder"},
 			wantVector: []string{"golang:1.11.0", "alpine:3.8"},
 		},
+		{
+			name:       "Registry Repo With Version",
+			args:       args{"FROM ghcr.io/org/image:1.0"},
+			wantVector: []string{"image:1.0"},
+		},
+		{
+			name:       "Registry Repo With Port",
+			args:       args{"FROM localhost:5000/img:1"},
+			wantVector: []string{"img:1"},
+		},
+		{
+			name:       "Repo With Version And Digest",
+			args:       args{"FROM image:1.2@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+			wantVector: []string{"image:1.2"},
+		},
 		{
 			name:       "Multiple Tag Vectors per Instruction",
 			args:       args{"FROM gofunky/golang:1.11.0-alpine3.8-master"},
@@ -520,7 +556,11 @@ func Test_toTagVector(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotVector := toTagVector(tt.args.inst); !cmp.Equal(gotVector, tt.wantVector) {
+			instructions, err := parseDockerfile(tt.args.inst)
+			if err != nil {
+				t.Fatalf("parseDockerfile() error = %v", err)
+			}
+			if gotVector := (Conventions{}).toTagVector(instructions[0]); !cmp.Equal(gotVector, tt.wantVector) {
 				t.Errorf("toTagVector() = %v, want %v", gotVector, tt.wantVector)
 			}
 		})
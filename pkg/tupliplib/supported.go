@@ -0,0 +1,114 @@
+package tupliplib
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// SupportedTagsFormat depicts the markup of a supported tags section.
+type SupportedTagsFormat string
+
+const (
+	// MarkdownFormat renders the supported tags as Markdown list.
+	MarkdownFormat SupportedTagsFormat = "markdown"
+	// HTMLFormat renders the supported tags as HTML list.
+	HTMLFormat SupportedTagsFormat = "html"
+)
+
+const (
+	// SupportedTagsStart is the marker that starts the supported tags section in a README.
+	SupportedTagsStart = "<!-- tuplip:supported-tags -->"
+	// SupportedTagsEnd is the marker that ends the supported tags section in a README.
+	SupportedTagsEnd = "<!-- tuplip:supported-tags-end -->"
+)
+
+// SupportedTagsOptions contain the parameters for rendering a supported tags section.
+type SupportedTagsOptions struct {
+	// Format is the markup of the section.
+	Format SupportedTagsFormat `name:"markup" enum:"markdown,html" default:"markdown" help:"the markup of the section (markdown or html)"`
+	// LinkPrefix is prepended to the Dockerfile paths of the links (e.g., `https://github.com/org/repo/blob/main/`).
+	LinkPrefix string `placeholder:"URL" help:"prepend the given URL to the Dockerfile paths of the links"`
+}
+
+// SupportedTags renders the entries of the library as list with one item per entry. Each item contains all tags of
+// the entry and links to the Dockerfile of the entry if it has a directory.
+func (l Library) SupportedTags(options SupportedTagsOptions) string {
+	var builder strings.Builder
+	if options.Format == HTMLFormat {
+		builder.WriteString("<ul>\n")
+	}
+	for _, entry := range l.Entries {
+		entry = entry.inherit(l.Header)
+		tags := append(append([]string{}, entry.Tags...), entry.SharedTags...)
+		link := entry.dockerfile()
+		if link != "" {
+			link = options.LinkPrefix + link
+		}
+		switch options.Format {
+		case HTMLFormat:
+			for i, tag := range tags {
+				tags[i] = "<code>" + html.EscapeString(tag) + "</code>"
+			}
+			item := strings.Join(tags, ", ")
+			if link != "" {
+				item = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(link), item)
+			}
+			builder.WriteString("  <li>" + item + "</li>\n")
+		default:
+			for i, tag := range tags {
+				tags[i] = "`" + tag + "`"
+			}
+			item := strings.Join(tags, ", ")
+			if link != "" {
+				item = fmt.Sprintf("[%s](%s)", item, strings.ReplaceAll(link, " ", "%20"))
+			}
+			builder.WriteString("- " + item + "\n")
+		}
+	}
+	if options.Format == HTMLFormat {
+		builder.WriteString("</ul>\n")
+	}
+	return builder.String()
+}
+
+// dockerfile returns the slash-separated path of the Dockerfile of the entry, or an empty path if the entry has no
+// directory.
+func (e LibraryEntry) dockerfile() string {
+	if e.Directory == "" {
+		return ""
+	}
+	name := Dockerfile
+	for _, field := range e.Fields {
+		if field.Name == LibraryFile {
+			name = field.Value
+		}
+	}
+	return path.Join(e.Directory, name)
+}
+
+// ReplaceSupportedTags replaces the content between the SupportedTagsStart and the SupportedTagsEnd markers of the
+// given README file by the given section. In simulation mode, the file is checked but not written.
+func (t *Tuplip) ReplaceSupportedTags(readme string, section string) error {
+	content, err := ioutil.ReadFile(readme)
+	if err != nil {
+		return err
+	}
+	text := string(content)
+	start := strings.Index(text, SupportedTagsStart)
+	end := strings.Index(text, SupportedTagsEnd)
+	if start < 0 || end < start {
+		return fmt.Errorf("the file '%s' has no section between the markers '%s' and '%s'", readme,
+			SupportedTagsStart, SupportedTagsEnd)
+	}
+	text = text[:start+len(SupportedTagsStart)] + "\n\n" + section + "\n" + text[end:]
+	logger.InfoWith("replacing supported tags").
+		String("file", readme).
+		Write()
+	if t.Simulate {
+		return nil
+	}
+	return ioutil.WriteFile(readme, []byte(text), 0644)
+}
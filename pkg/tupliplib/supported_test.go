@@ -0,0 +1,149 @@
+package tupliplib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLibrary_SupportedTags(t *testing.T) {
+	library := Library{
+		Header: LibraryEntry{Directory: "app"},
+		Entries: []LibraryEntry{
+			{
+				Tags:      []string{"1.2.0-alpine", "alpine"},
+				Directory: "1.2/alpine",
+			},
+			{
+				Tags:       []string{"1.2.0-bookworm"},
+				SharedTags: []string{"1.2.0", "latest"},
+				Fields:     []LibraryField{{LibraryFile, "Dockerfile.bookworm"}},
+			},
+			{
+				Tags: []string{"<edge>"},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		library Library
+		options SupportedTagsOptions
+		want    string
+	}{
+		{
+			name:    "Markdown",
+			library: library,
+			options: SupportedTagsOptions{Format: MarkdownFormat},
+			want: "- [`1.2.0-alpine`, `alpine`](1.2/alpine/Dockerfile)\n" +
+				"- [`1.2.0-bookworm`, `1.2.0`, `latest`](app/Dockerfile.bookworm)\n" +
+				"- [`<edge>`](app/Dockerfile)\n",
+		},
+		{
+			name:    "Markdown With Link Prefix",
+			library: Library{Entries: library.Entries[:1]},
+			options: SupportedTagsOptions{Format: MarkdownFormat, LinkPrefix: "https://github.com/gofunky/app/blob/main/"},
+			want:    "- [`1.2.0-alpine`, `alpine`](https://github.com/gofunky/app/blob/main/1.2/alpine/Dockerfile)\n",
+		},
+		{
+			name:    "Markdown Without Directory",
+			library: Library{Entries: []LibraryEntry{{Tags: []string{"1.0.0", "1"}}}},
+			options: SupportedTagsOptions{Format: MarkdownFormat, LinkPrefix: "https://example.com/"},
+			want:    "- `1.0.0`, `1`\n",
+		},
+		{
+			name:    "HTML",
+			library: library,
+			options: SupportedTagsOptions{Format: HTMLFormat},
+			want: "<ul>\n" +
+				"  <li><a href=\"1.2/alpine/Dockerfile\"><code>1.2.0-alpine</code>, <code>alpine</code></a></li>\n" +
+				"  <li><a href=\"app/Dockerfile.bookworm\"><code>1.2.0-bookworm</code>, <code>1.2.0</code>, " +
+				"<code>latest</code></a></li>\n" +
+				"  <li><a href=\"app/Dockerfile\"><code>&lt;edge&gt;</code></a></li>\n" +
+				"</ul>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.library.SupportedTags(tt.options); got != tt.want {
+				t.Errorf("SupportedTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceSupportedTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		simulate bool
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "Replace Section",
+			content: "# App\n\n## Supported tags\n\n" + SupportedTagsStart + "\n- `0.9.0`\n" + SupportedTagsEnd +
+				"\n\n## Usage\n",
+			want: "# App\n\n## Supported tags\n\n" + SupportedTagsStart + "\n\n- `1.0.0`, `1`\n\n" + SupportedTagsEnd +
+				"\n\n## Usage\n",
+		},
+		{
+			name:    "Empty Section",
+			content: SupportedTagsStart + SupportedTagsEnd,
+			want:    SupportedTagsStart + "\n\n- `1.0.0`, `1`\n\n" + SupportedTagsEnd,
+		},
+		{
+			name:     "Simulation",
+			content:  SupportedTagsStart + "\n- `0.9.0`\n" + SupportedTagsEnd,
+			simulate: true,
+			want:     SupportedTagsStart + "\n- `0.9.0`\n" + SupportedTagsEnd,
+		},
+		{
+			name:     "Simulated Missing End Marker",
+			content:  "# App\n" + SupportedTagsStart + "\n",
+			simulate: true,
+			wantErr:  true,
+		},
+		{
+			name:    "Missing End Marker",
+			content: "# App\n" + SupportedTagsStart + "\n",
+			wantErr: true,
+		},
+		{
+			name:    "Swapped Markers",
+			content: SupportedTagsEnd + "\n" + SupportedTagsStart + "\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readme := filepath.Join(t.TempDir(), "README.md")
+			if err := os.WriteFile(readme, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			tuplip := &Tuplip{Simulate: tt.simulate}
+			err := tuplip.ReplaceSupportedTags(readme, "- `1.0.0`, `1`\n")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReplaceSupportedTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := os.ReadFile(readme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr {
+				if string(got) != tt.content {
+					t.Errorf("ReplaceSupportedTags() modified the file to %q", got)
+				}
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReplaceSupportedTags() = %q, want %q", got, tt.want)
+			}
+			if err = tuplip.ReplaceSupportedTags(readme, "- `1.0.0`, `1`\n"); err != nil {
+				t.Fatal(err)
+			}
+			if again, _ := os.ReadFile(readme); string(again) != tt.want {
+				t.Errorf("ReplaceSupportedTags() is not idempotent, got %q", again)
+			}
+		})
+	}
+}
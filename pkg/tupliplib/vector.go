@@ -0,0 +1,109 @@
+package tupliplib
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// VectorKind depicts the kind of a tag vector.
+type VectorKind string
+
+const (
+	// AliasVector is an unversioned alias tag vector.
+	AliasVector VectorKind = "alias"
+
+	// DependencyVector is a versioned dependency tag vector.
+	DependencyVector VectorKind = "dependency"
+
+	// RootVector is a versioned root tag vector.
+	RootVector VectorKind = "root"
+)
+
+var (
+	// vectorAliasPattern matches the characters that are allowed in a tag vector alias.
+	vectorAliasPattern = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+	// vectorVersionPattern matches the characters that are allowed in a tag vector version.
+	vectorVersionPattern = regexp.MustCompile(`^[A-Za-z0-9_.+]+$`)
+)
+
+// VectorOptions define the tag generation options that only apply to a single tag vector.
+// They extend the options of the Tuplip instance, they never disable an option that is enabled there.
+type VectorOptions struct {
+	// ExcludeMajor excludes the major versions of the vector from the considered version variants.
+	ExcludeMajor bool `json:"exclude-major,omitempty" yaml:"exclude-major,omitempty"`
+	// ExcludeMinor excludes the minor versions of the vector from the considered version variants.
+	ExcludeMinor bool `json:"exclude-minor,omitempty" yaml:"exclude-minor,omitempty"`
+	// ExcludeBase excludes the base alias of the vector without version suffix from the considered version variants.
+	ExcludeBase bool `json:"exclude-base,omitempty" yaml:"exclude-base,omitempty"`
+}
+
+// VectorSpec is the structured description of a single tag vector.
+type VectorSpec struct {
+	// Kind is the kind of the tag vector. It is derived from the alias and the version if empty.
+	Kind VectorKind `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// Alias is the alias of the tag vector. Root tag vectors have no alias.
+	Alias string `json:"alias,omitempty" yaml:"alias,omitempty"`
+	// Version is the version of a dependency or root tag vector.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Options are the tag generation options for this vector only.
+	Options VectorOptions `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ParseVector parses the given tag vector notation (e.g., `alpine:3.8`, `_:1.0.0`, or `feature`).
+// The kind is derived from the alias and the version.
+func ParseVector(text string) VectorSpec {
+	alias, version, _ := strings.Cut(text, VersionSeparator)
+	return VectorSpec{Alias: strings.TrimSpace(alias), Version: strings.TrimSpace(version)}
+}
+
+// kind returns the explicit kind of the vector or derives it from the given alias and version.
+func (v VectorSpec) kind() VectorKind {
+	if v.Kind != "" {
+		return v.Kind
+	}
+	if v.Alias == "" || v.Alias == WildcardDependency {
+		return RootVector
+	}
+	if v.Version == "" {
+		return AliasVector
+	}
+	return DependencyVector
+}
+
+// alias returns the alias that identifies the vector in a tuplip stream.
+func (v VectorSpec) alias() string {
+	if v.kind() == RootVector {
+		return WildcardDependency
+	}
+	return v.Alias
+}
+
+// String returns the tag vector notation of the vector (e.g., `alpine:3.8` or `_:1.0.0`).
+func (v VectorSpec) String() string {
+	if v.kind() == AliasVector {
+		return v.Alias
+	}
+	return v.alias() + VersionSeparator + v.Version
+}
+
+// Validate implements validation.Validatable and checks the consistency of the vector kind, alias and version.
+func (v VectorSpec) Validate() error {
+	kind := v.kind()
+	return validation.ValidateStruct(&v,
+		validation.Field(&v.Kind, validation.In(AliasVector, DependencyVector, RootVector)),
+		validation.Field(&v.Alias,
+			validation.When(kind != RootVector, validation.Required),
+			validation.When(kind == RootVector, validation.In(WildcardDependency).
+				Error("must be empty or '"+WildcardDependency+"' for root vectors")),
+			validation.When(kind != RootVector, validation.Match(vectorAliasPattern)),
+		),
+		validation.Field(&v.Version,
+			validation.When(kind != AliasVector, validation.Required),
+			validation.When(kind == AliasVector, validation.Empty.Error("must be empty for alias vectors")),
+			validation.Match(vectorVersionPattern),
+		),
+	)
+}
@@ -0,0 +1,58 @@
+variable "ALPINE_VERSION" {
+  default = "3.19"
+}
+
+variable "REGISTRY" {
+  default = "ghcr.io/gofunky"
+}
+
+group "default" {
+  targets = ["app", "worker"]
+}
+
+target "_common" {
+  context = "app"
+  args = {
+    ALPINE_VERSION = ALPINE_VERSION
+  }
+}
+
+target "app" {
+  inherits = ["_common"]
+  target   = "runtime"
+  tags     = ["${REGISTRY}/app:latest"]
+}
+
+target "debug" {
+  inherits  = ["_common"]
+  target    = "debug"
+  platforms = ["linux/amd64", "linux/arm64"]
+}
+
+target "worker" {
+  context    = "worker"
+  dockerfile = "Dockerfile.worker"
+  args = {
+    VERSION = "2.1"
+  }
+}
+
+target "_edge" {
+  context = "app"
+  target  = "debug"
+  args = {
+    VERSION = "1.1.0"
+  }
+}
+
+target "_stable" {
+  target = "runtime"
+  args = {
+    ALPINE_VERSION = "3.17"
+    VERSION        = "1.2.0"
+  }
+}
+
+target "stable" {
+  inherits = ["_edge", "_stable"]
+}
@@ -0,0 +1,17 @@
+module example.com/service
+
+go 1.22.6
+
+toolchain go1.23.1
+
+require (
+	github.com/alecthomas/kong v0.9.0
+	github.com/blang/semver v3.5.1+incompatible
+	github.com/blang/semver/v4 v4.0.0
+	github.com/example/bar v0.0.0-20240101120000-abcdefabcdef
+	github.com/example/baz v1.3.0-rc.1
+	github.com/gofunky/automi v0.1.0
+	github.com/google/go-cmp v0.6.0 // indirect
+)
+
+replace github.com/gofunky/automi => github.com/gofunky/automi v0.2.0